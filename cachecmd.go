@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/xeyossr/anitr-cli/internal/cache"
+)
+
+// newCacheCmd, kaynak yanıtlarının önbelleğini yöneten cache komutunu oluşturur.
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Arama, sezon ve bölüm listelerinin önbelleğini yönetir",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "path",
+		Short: "Önbellek dizininin yolunu yazdırır",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := cache.Dir()
+			if err != nil {
+				return err
+			}
+			fmt.Println(dir)
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "clear",
+		Short: "Önbellekteki tüm yanıtları siler",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			removed, err := cache.Clear()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%d kayıt önbellekten silindi.\n", removed)
+			return nil
+		},
+	})
+
+	return cmd
+}
@@ -0,0 +1,492 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"github.com/xeyossr/anitr-cli/internal/flags"
+	"github.com/xeyossr/anitr-cli/internal/history"
+	"github.com/xeyossr/anitr-cli/internal/models"
+	"github.com/xeyossr/anitr-cli/internal/player"
+	"github.com/xeyossr/anitr-cli/internal/sources"
+	"github.com/xeyossr/anitr-cli/internal/utils"
+)
+
+// cliOptions, etkileşimsiz alt komutların ortak bayraklarını tutar.
+type cliOptions struct {
+	source  string
+	json    bool
+	isMovie bool
+}
+
+// searchResult, search komutunun çıktı satırıdır.
+type searchResult struct {
+	ID     *int   `json:"id,omitempty"`
+	Slug   string `json:"slug,omitempty"`
+	Title  string `json:"title"`
+	Type   string `json:"type"`
+	Poster string `json:"poster,omitempty"`
+	Source string `json:"source"`
+}
+
+// episodeResult, episodes komutunun çıktı satırıdır.
+type episodeResult struct {
+	Season         int    `json:"season"`
+	Episode        int    `json:"episode"`
+	AbsoluteNumber int    `json:"absolute_number"`
+	Title          string `json:"title"`
+	AirDate        string `json:"air_date,omitempty"`
+	ID             string `json:"id,omitempty"`
+}
+
+// streamResult, streams komutunun çıktı satırıdır.
+type streamResult struct {
+	Quality   string            `json:"quality"`
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Subtitles []subtitleResult  `json:"subtitles,omitempty"`
+}
+
+// subtitleResult, bir akışa ait altyazı bilgisidir.
+type subtitleResult struct {
+	Language string `json:"language"`
+	URL      string `json:"url"`
+}
+
+// addSourceFlags, --source ve --json bayraklarını komuta ekler.
+func addSourceFlags(cmd *cobra.Command, opts *cliOptions) {
+	cmd.Flags().StringVarP(&opts.source, "source", "s", defaultSourceName(),
+		fmt.Sprintf("Kullanılacak kaynak (%s)", strings.Join(sourceNames(), ", ")))
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Çıktıyı tablo yerine JSON olarak yazdırır")
+}
+
+// defaultSourceName, bayraklar için varsayılan kaynağın adını döner.
+func defaultSourceName() string {
+	if appConfig.Source != "" {
+		return appConfig.Source
+	}
+	list := sources.List()
+	if len(list) == 0 {
+		return ""
+	}
+	return list[0].Name
+}
+
+// sourceNames, kayıtlı kaynakların adlarını döner.
+func sourceNames() []string {
+	var names []string
+	for _, e := range sources.List() {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+// animeRef, komut satırında verilen ID veya slug'dan bir Anime oluşturur.
+// Sayısal değerler hem ID hem slug olarak atanır; her kaynak ihtiyaç duyduğunu kullanır.
+func animeRef(ref string, isMovie bool) models.Anime {
+	anime := models.Anime{Title: ref, Slug: utils.Ptr(ref)}
+	if id, err := strconv.Atoi(ref); err == nil {
+		anime.ID = utils.Ptr(id)
+	}
+	if isMovie {
+		anime.TitleType = utils.Ptr("movie")
+	}
+	return anime
+}
+
+// printJSON, verilen değeri girintili JSON olarak standart çıktıya yazar.
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printTable, başlık ve satırları hizalı tablo olarak standart çıktıya yazar.
+func printTable(header []string, rows [][]string) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// findEpisode, sezon ve bölüm numarasına göre bölümün listedeki indeksini döner.
+func findEpisode(episodes []models.Episode, season, number int) int {
+	for i, ep := range episodes {
+		if ep.Season == season && ep.Number == number {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalizeTitle, başlığı karşılaştırma için küçük harfe çevirir, Türkçe karakterleri
+// ASCII'ye dönüştürür ve harf/rakam dışındaki karakterleri atar.
+func normalizeTitle(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(utils.NormalizeTurkishToASCII(title)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// bestMatch, arama sonuçları içinde sorguyla eşleşen animeyi seçer.
+// Tek sonuç varsa ya da başlığı sorguyla birebir eşleşen tek bir sonuç varsa true döner;
+// aksi hâlde eşleşme belirsizdir ve seçim kullanıcıya bırakılmalıdır.
+func bestMatch(query string, results []models.Anime) (models.Anime, bool) {
+	if len(results) == 1 {
+		return results[0], true
+	}
+
+	normalizedQuery := normalizeTitle(query)
+	var matches []models.Anime
+	for _, a := range results {
+		if normalizeTitle(a.Title) == normalizedQuery {
+			matches = append(matches, a)
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0], true
+	}
+	return models.Anime{}, false
+}
+
+func newSearchCmd(logger *utils.Logger) *cobra.Command {
+	opts := &cliOptions{}
+	cmd := &cobra.Command{
+		Use:   "search <sorgu>",
+		Short: "Anime arar ve sonuçları listeler",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entry, err := sources.Lookup(opts.source)
+			if err != nil {
+				return err
+			}
+
+			results, err := entry.Source.GetSearchData(cmd.Context(), strings.Join(args, " "))
+			if err != nil {
+				logger.LogError(err)
+				return fmt.Errorf("arama başarısız: %w", err)
+			}
+
+			out := make([]searchResult, 0, len(results))
+			for _, a := range results {
+				r := searchResult{ID: a.ID, Title: a.Title, Type: "tv", Poster: a.ImageURL, Source: entry.Name}
+				if a.Slug != nil {
+					r.Slug = *a.Slug
+				}
+				if a.TitleType != nil && strings.ToLower(*a.TitleType) == "movie" {
+					r.Type = "movie"
+				}
+				out = append(out, r)
+			}
+
+			if opts.json {
+				return printJSON(out)
+			}
+
+			rows := make([][]string, 0, len(out))
+			for _, r := range out {
+				ref := r.Slug
+				if r.ID != nil {
+					ref = strconv.Itoa(*r.ID)
+				}
+				rows = append(rows, []string{ref, r.Type, r.Title})
+			}
+			return printTable([]string{"ID/SLUG", "TÜR", "BAŞLIK"}, rows)
+		},
+	}
+	addSourceFlags(cmd, opts)
+	return cmd
+}
+
+func newEpisodesCmd(logger *utils.Logger) *cobra.Command {
+	opts := &cliOptions{}
+	cmd := &cobra.Command{
+		Use:   "episodes <id|slug>",
+		Short: "Bir animenin bölümlerini listeler",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entry, err := sources.Lookup(opts.source)
+			if err != nil {
+				return err
+			}
+
+			episodes, _, _, err := getEpisodesAndNames(cmd.Context(), entry.Source, opts.isMovie, animeRef(args[0], opts.isMovie), logger)
+			if err != nil {
+				return err
+			}
+
+			out := make([]episodeResult, 0, len(episodes))
+			for _, ep := range episodes {
+				r := episodeResult{
+					Season:         ep.Season,
+					Episode:        ep.Number,
+					AbsoluteNumber: ep.AbsoluteNumber,
+					Title:          ep.Title,
+					ID:             ep.ID,
+				}
+				if !ep.AirDate.IsZero() {
+					r.AirDate = ep.AirDate.Format("2006-01-02")
+				}
+				out = append(out, r)
+			}
+
+			if opts.json {
+				return printJSON(out)
+			}
+
+			rows := make([][]string, 0, len(out))
+			for _, r := range out {
+				rows = append(rows, []string{strconv.Itoa(r.Season), strconv.Itoa(r.Episode), strconv.Itoa(r.AbsoluteNumber), r.Title})
+			}
+			return printTable([]string{"SEZON", "BÖLÜM", "SIRA", "BAŞLIK"}, rows)
+		},
+	}
+	addSourceFlags(cmd, opts)
+	cmd.Flags().BoolVar(&opts.isMovie, "movie", false, "İçeriği film olarak ele alır")
+	return cmd
+}
+
+func newStreamsCmd(logger *utils.Logger) *cobra.Command {
+	opts := &cliOptions{}
+	var fansubID string
+	cmd := &cobra.Command{
+		Use:   "streams <id|slug> [sezon] [bölüm]",
+		Short: "Bir bölümün video akışlarını listeler",
+		Args:  cobra.RangeArgs(1, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entry, err := sources.Lookup(opts.source)
+			if err != nil {
+				return err
+			}
+
+			anime := animeRef(args[0], opts.isMovie)
+			episodes, _, isMovie, err := getEpisodesAndNames(cmd.Context(), entry.Source, opts.isMovie, anime, logger)
+			if err != nil {
+				return err
+			}
+
+			index := 0
+			if !isMovie {
+				if len(args) < 3 {
+					return fmt.Errorf("dizi için sezon ve bölüm numarası gerekli")
+				}
+				season, err := strconv.Atoi(args[1])
+				if err != nil {
+					return fmt.Errorf("geçersiz sezon numarası: %s", args[1])
+				}
+				number, err := strconv.Atoi(args[2])
+				if err != nil {
+					return fmt.Errorf("geçersiz bölüm numarası: %s", args[2])
+				}
+				index = findEpisode(episodes, season, number)
+				if index == -1 {
+					return fmt.Errorf("%d. sezon %d. bölüm bulunamadı", season, number)
+				}
+			}
+
+			id, slug := getAnimeIDs(anime)
+			if fansubID != "" {
+				fansubID, err = resolveFansubID(cmd.Context(), entry.Source, episodes[index], id, slug, fansubID)
+				if err != nil {
+					return err
+				}
+			}
+
+			streams, err := fetchStreams(cmd.Context(), entry.Source, episodes, index, id, slug, isMovie, fansubID)
+			if err != nil {
+				logger.LogError(err)
+				return err
+			}
+
+			out := make([]streamResult, 0, len(streams))
+			for _, s := range streams {
+				r := streamResult{Quality: s.Quality, URL: s.URL, Headers: s.Headers}
+				for _, sub := range s.Subtitles {
+					r.Subtitles = append(r.Subtitles, subtitleResult{Language: sub.Language, URL: sub.URL})
+				}
+				out = append(out, r)
+			}
+
+			if opts.json {
+				return printJSON(out)
+			}
+
+			rows := make([][]string, 0, len(out))
+			for _, s := range streams {
+				rows = append(rows, []string{s.Quality, s.URL, s.SubtitleURL()})
+			}
+			return printTable([]string{"ÇÖZÜNÜRLÜK", "URL", "ALTYAZI"}, rows)
+		},
+	}
+	addSourceFlags(cmd, opts)
+	cmd.Flags().BoolVar(&opts.isMovie, "movie", false, "İçeriği film olarak ele alır (sezon ve bölüm gerekmez)")
+	cmd.Flags().StringVar(&fansubID, "fansub", "", "Fansub destekleyen kaynaklarda kullanılacak fansub adı veya ID'si")
+	return cmd
+}
+
+func newPlayCmd(f *flags.Flags, logger *utils.Logger) *cobra.Command {
+	var (
+		sourceName string
+		season     int
+		episode    int
+		quality    string
+		fansub     string
+	)
+	cmd := &cobra.Command{
+		Use:   "play <başlık>",
+		Short: "Menülere girmeden bir bölümü doğrudan oynatır",
+		Long: `Başlığı aratır, en iyi eşleşen animeyi seçer ve istenen bölümü doğrudan oynatır.
+Birden fazla sonuç eşleşirse anime seçimi için liste gösterilir.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entry, err := sources.Lookup(sourceName)
+			if err != nil {
+				return err
+			}
+			mediaPlayer, err := newPlayer(f)
+			if err != nil {
+				return err
+			}
+			title := strings.Join(args, " ")
+
+			results, err := entry.Source.GetSearchData(cmd.Context(), title)
+			if err != nil {
+				logger.LogError(err)
+				return fmt.Errorf("arama başarısız: %w", err)
+			}
+			if len(results) == 0 {
+				return fmt.Errorf("'%s' için sonuç bulunamadı", title)
+			}
+
+			// Eşleşme belirsizse anime seçimini kullanıcıya bırak
+			anime, ok := bestMatch(title, results)
+			if !ok {
+				names := make([]string, 0, len(results))
+				for _, a := range results {
+					names = append(names, a.Title)
+				}
+				uiMode := defaultUIMode()
+				if f.RofiMode {
+					uiMode = "rofi"
+				}
+				anime, _, _ = selectAnime(names, results, uiMode, false, f.RofiFlags, nil, logger)
+			}
+
+			isMovie := anime.TitleType != nil && strings.ToLower(*anime.TitleType) == "movie"
+			episodes, episodeNames, isMovie, err := getEpisodesAndNames(cmd.Context(), entry.Source, isMovie, anime, logger)
+			if err != nil {
+				return err
+			}
+
+			index := 0
+			if !isMovie {
+				index = findEpisode(episodes, season, episode)
+				if index == -1 {
+					return fmt.Errorf("%s: %d. sezon %d. bölüm bulunamadı", anime.Title, season, episode)
+				}
+			}
+
+			id, slug := getAnimeIDs(anime)
+			fansubID := ""
+			if fansub != "" {
+				fansubID, err = resolveFansubID(cmd.Context(), entry.Source, episodes[index], id, slug, fansub)
+				if err != nil {
+					return err
+				}
+			}
+
+			streams, err := fetchStreams(cmd.Context(), entry.Source, episodes, index, id, slug, isMovie, fansubID)
+			if err != nil {
+				logger.LogError(err)
+				return err
+			}
+
+			stream, found := models.SelectStream(streams, quality)
+			if quality != "" && !found {
+				fmt.Printf("[!] '%s' çözünürlüğü bulunamadı, '%s' oynatılıyor.\n", quality, stream.Quality)
+			}
+
+			playerTitle := fmt.Sprintf("%s - %s", anime.Title, episodeNames[index])
+			if isMovie {
+				playerTitle = anime.Title
+			}
+			subtitle := playerSubtitle(entry.Source, stream)
+
+			fmt.Printf("Oynatılıyor: %s (%s)\n", playerTitle, stream.Quality)
+			hist, err := loadHistory()
+			if err != nil {
+				return err
+			}
+			startTime := resumePosition(hist, history.Key(entry.Name, id, slug), episodes[index])
+			if startTime > 0 {
+				fmt.Printf("Kaldığı yerden devam ediliyor: %s\n", formatPosition(startTime))
+			}
+
+			session, err := mediaPlayer.Play(player.Params{
+				URL:         stream.URL,
+				Title:       playerTitle,
+				SubtitleURL: subtitle,
+				Headers:     stream.Headers,
+				StartTime:   startTime,
+			})
+			if err != nil {
+				return err
+			}
+
+			if !f.DisableRPC {
+				loggedIn := false
+				posterURL, malURL := animePosterURL(anime), animeMyAnimeListURL(anime)
+				go updateDiscordRPC(episodeNames, index, anime.Title, entry.Label, posterURL, malURL, false, logger, &loggedIn)
+				go watchPauseEvents(session, func(paused bool) {
+					updateDiscordRPC(episodeNames, index, anime.Title, entry.Label, posterURL, malURL, paused, logger, &loggedIn)
+				})
+			}
+
+			// Ctrl+C oynatıcıyı da hata koduyla kapatır; konum alınabildiyse yine kaydedilir
+			status, waitErr := session.Wait()
+			interrupted := cmd.Context().Err() != nil
+			if waitErr != nil && !status.Known() {
+				if interrupted {
+					return nil
+				}
+				return fmt.Errorf("oynatıcı çalışırken hata: %w", waitErr)
+			}
+
+			hist.RecordEpisode(history.Show{
+				Source:     entry.Name,
+				AnimeID:    id,
+				Slug:       slug,
+				Title:      anime.Title,
+				PosterURL:  anime.ImageURL,
+				IsMovie:    isMovie,
+				Fansub:     fansubID,
+				Resolution: stream.Quality,
+			}, playbackRecord(episodes[index], status, f.WatchedPercent))
+			if err := hist.Save(); err != nil {
+				return err
+			}
+			if waitErr != nil && !interrupted {
+				return fmt.Errorf("oynatıcı çalışırken hata: %w", waitErr)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&sourceName, "source", "s", defaultSourceName(),
+		fmt.Sprintf("Kullanılacak kaynak (%s)", strings.Join(sourceNames(), ", ")))
+	cmd.Flags().IntVar(&season, "season", 1, "Sezon numarası")
+	cmd.Flags().IntVarP(&episode, "episode", "e", 1, "Sezon içindeki bölüm numarası")
+	cmd.Flags().StringVarP(&quality, "quality", "q", "", "Tercih edilen çözünürlük (örn: 1080p), boşsa en yüksek")
+	cmd.Flags().StringVar(&fansub, "fansub", "", "Fansub destekleyen kaynaklarda kullanılacak fansub adı veya ID'si")
+	return cmd
+}
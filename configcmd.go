@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xeyossr/anitr-cli/internal/config"
+)
+
+// newConfigCmd, yapılandırma dosyasını yöneten config komutunu oluşturur.
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Yapılandırma dosyasını görüntüler ve düzenler",
+		Long: fmt.Sprintf(`Kalıcı ayarları yönetir. Komut satırı bayrakları dosyadaki değerleri geçersiz kılar.
+
+Ayarlar: %s`, strings.Join(config.Keys(), ", ")),
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "path",
+		Short: "Yapılandırma dosyasının yolunu yazdırır",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := config.Path()
+			if err != nil {
+				return err
+			}
+			fmt.Println(path)
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get [anahtar]",
+		Short: "Bir ayarın değerini, anahtar verilmezse tüm ayarları yazdırır",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				value, err := appConfig.Get(args[0])
+				if err != nil {
+					return err
+				}
+				fmt.Println(value)
+				return nil
+			}
+
+			rows := make([][]string, 0, len(config.Keys()))
+			for _, key := range config.Keys() {
+				value, _ := appConfig.Get(key)
+				rows = append(rows, []string{key, value})
+			}
+			return printTable([]string{"AYAR", "DEĞER"}, rows)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set <anahtar> <değer>",
+		Short: "Bir ayarı değiştirir ve dosyaya kaydeder",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := config.Path()
+			if err != nil {
+				return err
+			}
+			cfg, err := config.SetFile(path, args[0], args[1])
+			if err != nil {
+				return err
+			}
+			appConfig = cfg
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "edit",
+		Short: "Yapılandırma dosyasını düzenleyicide açar ($VISUAL veya $EDITOR)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := config.Path()
+			if err != nil {
+				return err
+			}
+
+			// Dosya yoksa mevcut ayarlarla oluşturulur
+			if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+				if err := appConfig.Save(path); err != nil {
+					return err
+				}
+			}
+
+			editor := strings.Fields(editorCommand())
+			editCmd := exec.Command(editor[0], append(editor[1:], path)...)
+			editCmd.Stdin, editCmd.Stdout, editCmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+			if err := editCmd.Run(); err != nil {
+				return fmt.Errorf("düzenleyici çalıştırılamadı: %w", err)
+			}
+
+			// Düzenlenen dosyanın geçerli olduğu kontrol edilir
+			if _, err := config.LoadFile(path); err != nil {
+				return fmt.Errorf("yapılandırma dosyası hatalı: %w", err)
+			}
+			return nil
+		},
+	})
+
+	return cmd
+}
+
+// editorCommand, kullanıcının tercih ettiği düzenleyiciyi döner.
+func editorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(env)); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/xeyossr/anitr-cli/internal/downloader"
+	"github.com/xeyossr/anitr-cli/internal/models"
+	"github.com/xeyossr/anitr-cli/internal/sources"
+	"github.com/xeyossr/anitr-cli/internal/utils"
+)
+
+// openDownloadStore, veri dizinindeki kalıcı indirme kuyruğunu açar.
+func openDownloadStore() (*downloader.Store, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return nil, err
+	}
+	return downloader.OpenStore(dir)
+}
+
+// openPersistentQueue, kalıcı kuyruğu açar ve ona bağlı bir indirme kuyruğu oluşturur.
+// Kayıt dosyası açılamazsa uyarı verilir ve kuyruk kaydedilmeden çalışır (store nil döner).
+func openPersistentQueue(logger *utils.Logger) (*downloader.Store, *downloader.Queue) {
+	store, err := openDownloadStore()
+	if err != nil {
+		logger.LogError(err)
+		fmt.Printf("[!] İndirme kuyruğu kaydedilemeyecek: %v\n", err)
+	}
+	return store, persistentQueue(store, logger)
+}
+
+// persistentQueue, işleri bitince sonucunu kalıcı kuyruğa işleyen bir indirme kuyruğu oluşturur.
+// store nil ise kuyruk kaydedilmeden çalışır.
+func persistentQueue(store *downloader.Store, logger *utils.Logger) *downloader.Queue {
+	queue := downloader.NewQueue(appConfig.DownloadConcurrency)
+	queue.Subtitles = subtitleOptions()
+	if store != nil {
+		queue.OnFinish = func(job downloader.Job, err error) {
+			if saveErr := store.Finish(job, err); saveErr != nil {
+				logger.LogError(saveErr)
+			}
+		}
+	}
+	return queue
+}
+
+// subtitleOptions, altyazı kaydetme ayarlarını döner.
+func subtitleOptions() downloader.SubtitleOptions {
+	return downloader.SubtitleOptions{Format: appConfig.SubtitleFormat, Mux: appConfig.MuxSubtitles}
+}
+
+// streamSubtitle, ayarlarda altyazı indirme açıksa ve kaynak ayrı altyazı dosyası sağlıyorsa
+// akışın altyazısının adresini ve dilini döner.
+func streamSubtitle(source models.AnimeSource, stream models.Stream) (string, string) {
+	if !appConfig.DownloadSubtitles || !sourceCapabilities(source).Captions || len(stream.Subtitles) == 0 {
+		return "", ""
+	}
+	return stream.Subtitles[0].URL, stream.Subtitles[0].Language
+}
+
+// existingDownload, path için daha önce indirilmiş bir dosya olup olmadığını kontrol eder.
+// Tamamlanmış (veya doğrulanamayan) bir dosya varsa yolu döner; yarım kalmış dosyalar yeniden
+// indirilecekleri için yok sayılır.
+func existingDownload(ctx context.Context, path, url string, headers map[string]string, logger *utils.Logger) string {
+	state, existing, err := downloader.CheckExisting(ctx, path, url, headers)
+	if err != nil {
+		logger.LogError(err)
+		return ""
+	}
+	switch state {
+	case downloader.ExistingComplete, downloader.ExistingUnknown:
+		return existing
+	case downloader.ExistingIncomplete:
+		fmt.Printf("[!] Mevcut dosya eksik, yeniden indirilecek: %s\n", existing)
+	}
+	return ""
+}
+
+// overwriteDownloads, daha önce indirilmiş dosyaları kullanıcıya sorar; yeniden indirilmesi
+// seçilirse dosyaları siler ve true döner.
+func overwriteDownloads(app App, paths []string, logger *utils.Logger) bool {
+	label := fmt.Sprintf("Zaten indirilmiş: %s ", paths[0])
+	if len(paths) > 1 {
+		label = fmt.Sprintf("%d dosya zaten indirilmiş ", len(paths))
+	}
+	choice, err := showSelection(app, []string{"Atla", "Yeniden indir"}, label, "", nil)
+	if !utils.CheckErr(err, logger) || len(choice) == 0 || choice[0] != "Yeniden indir" {
+		return false
+	}
+	for _, path := range paths {
+		if err := downloader.Remove(path); err != nil {
+			logger.LogError(err)
+		}
+	}
+	return true
+}
+
+// enqueueDownload, indirmeyi kalıcı kuyruğa kaydeder ve çalıştırılmak üzere kuyruğa ekler.
+func enqueueDownload(store *downloader.Store, queue *downloader.Queue, job downloader.Job, origin downloader.Origin, logger *utils.Logger) {
+	if store != nil {
+		record, err := store.Add(job.Name, job.URL, job.Path, origin)
+		if err != nil {
+			logger.LogError(err)
+		} else {
+			job.ID = record.ID
+		}
+	}
+	job.Refresh = downloadRefresher(origin, logger)
+	queue.Add(job)
+}
+
+// downloadRefresher, süresi dolan indirme bağlantısını kaynağın GetWatchData'sı üzerinden yeniden alan fonksiyonu döner.
+func downloadRefresher(origin downloader.Origin, logger *utils.Logger) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		entry, err := sources.Lookup(origin.Source)
+		if err != nil {
+			return "", err
+		}
+
+		anime := models.Anime{Slug: utils.Ptr(origin.Slug)}
+		if origin.AnimeID != 0 {
+			anime.ID = utils.Ptr(origin.AnimeID)
+		}
+		if origin.IsMovie {
+			anime.TitleType = utils.Ptr("movie")
+		}
+
+		episodes, _, isMovie, err := getEpisodesAndNames(ctx, entry.Source, origin.IsMovie, anime, logger)
+		if err != nil {
+			return "", err
+		}
+
+		index := 0
+		if !isMovie {
+			index = findEpisode(episodes, origin.Season, origin.Episode)
+			if index == -1 {
+				for i, ep := range episodes {
+					if ep.AbsoluteNumber == origin.AbsoluteNumber {
+						index = i
+						break
+					}
+				}
+			}
+			if index == -1 {
+				return "", fmt.Errorf("bölüm artık kaynakta bulunamadı")
+			}
+		}
+
+		streams, err := fetchStreams(ctx, entry.Source, episodes, index, origin.AnimeID, origin.Slug, isMovie, origin.FansubID)
+		if err != nil {
+			return "", err
+		}
+		stream, _ := models.SelectStream(streams, origin.Quality)
+		if stream.URL == "" {
+			return "", fmt.Errorf("video akışı bulunamadı")
+		}
+		return stream.URL, nil
+	}
+}
+
+// newDownloadsCmd, kalıcı indirme kuyruğunu yöneten downloads komutunu oluşturur.
+func newDownloadsCmd(logger *utils.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "downloads",
+		Short: "Kaydedilmiş indirme kuyruğunu listeler, devam ettirir ve temizler",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "Kuyruktaki indirmeleri listeler",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := openDownloadStore()
+			if err != nil {
+				return err
+			}
+
+			records := store.List()
+			if len(records) == 0 {
+				fmt.Println("İndirme kuyruğu boş.")
+				return nil
+			}
+
+			rows := make([][]string, 0, len(records))
+			for _, r := range records {
+				rows = append(rows, []string{r.ID, string(r.Status), r.Name, r.Path, r.Error})
+			}
+			return printTable([]string{"ID", "DURUM", "AD", "DOSYA", "HATA"}, rows)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "resume [id...]",
+		Short: "Tamamlanmamış indirmeleri (veya verilenleri) kaldığı yerden devam ettirir",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := openDownloadStore()
+			if err != nil {
+				return err
+			}
+
+			var records []downloader.Record
+			if len(args) == 0 {
+				for _, r := range store.List() {
+					if r.Unfinished() {
+						records = append(records, r)
+					}
+				}
+			} else {
+				for _, id := range args {
+					r, ok := store.Get(id)
+					if !ok {
+						return fmt.Errorf("indirme bulunamadı: %s", id)
+					}
+					records = append(records, r)
+				}
+			}
+			if len(records) == 0 {
+				fmt.Println("Devam ettirilecek indirme yok.")
+				return nil
+			}
+
+			queue := persistentQueue(store, logger)
+			for _, r := range records {
+				job := r.Job()
+				job.Refresh = downloadRefresher(r.Origin, logger)
+				queue.Add(job)
+			}
+
+			summary := queue.Run(cmd.Context())
+			summary.Print(os.Stdout)
+			if summary.Count(downloader.StatusFailed) > 0 {
+				return fmt.Errorf("bazı indirmeler başarısız oldu")
+			}
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "cancel <id...>",
+		Short: "İndirmeleri kuyruktan çıkarır ve yarım kalan dosyalarını siler",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := openDownloadStore()
+			if err != nil {
+				return err
+			}
+			for _, id := range args {
+				if err := store.Remove(id); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+
+	var clearAll bool
+	clearCmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Tamamlanan indirmeleri kuyruktan siler",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := openDownloadStore()
+			if err != nil {
+				return err
+			}
+			removed, err := store.Clear(clearAll)
+			fmt.Printf("%d indirme kuyruktan silindi.\n", removed)
+			return err
+		},
+	}
+	clearCmd.Flags().BoolVar(&clearAll, "all", false, "Tamamlanmamış indirmeleri de yarım dosyalarıyla birlikte siler")
+	cmd.AddCommand(clearCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "verify [dizin]",
+		Short: "İndirilen dosyaları manifestlerle karşılaştırıp kesik veya eksik olanları bulur",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := downloadRoot()
+			if len(args) == 1 {
+				dir = args[0]
+			}
+
+			problems, checked, err := downloader.Verify(dir)
+			if err != nil {
+				return err
+			}
+			if len(problems) == 0 {
+				fmt.Printf("%d dosya kontrol edildi, sorun bulunamadı.\n", checked)
+				return nil
+			}
+
+			rows := make([][]string, 0, len(problems))
+			for _, p := range problems {
+				rows = append(rows, []string{p.Kind, p.Path, p.Detail})
+			}
+			if err := printTable([]string{"DURUM", "DOSYA", "AÇIKLAMA"}, rows); err != nil {
+				return err
+			}
+			return fmt.Errorf("%d dosya kontrol edildi, %d sorun bulundu", checked, len(problems))
+		},
+	})
+
+	return cmd
+}
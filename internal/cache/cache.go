@@ -0,0 +1,197 @@
+// cache paketi, kaynak API'lerinden gelen yanıtları önbellek dizininde saklar.
+//
+// Kayıtlar kaynak adı ve uç nokta yolundan oluşan bir anahtarla tutulur; böylece
+// kaynağın aynası değişse de önbellek geçerli kalır. Her veri türünün kendi geçerlilik
+// süresi vardır. Video bağlantıları gibi kısa ömürlü yanıtlar önbelleğe alınmaz.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/xeyossr/anitr-cli/internal/paths"
+)
+
+// Veri türlerine göre geçerlilik süreleri.
+const (
+	SearchTTL   = time.Hour      // Arama sonuçları
+	SeasonsTTL  = 24 * time.Hour // Anime bilgileri ve sezon listeleri
+	EpisodesTTL = 6 * time.Hour  // Sezonların bölüm listeleri (yeni bölümler eklenebilir)
+)
+
+// subDir, önbellek dizini içinde yanıtların tutulduğu dizindir.
+const subDir = "responses"
+
+var disabled atomic.Bool
+
+// SetEnabled, önbelleği açar veya kapatır (--no-cache). Kapalıyken her istek kaynağa gider
+// ve yanıtlar kaydedilmez.
+func SetEnabled(enabled bool) {
+	disabled.Store(!enabled)
+}
+
+// entry, diskteki tek bir önbellek kaydıdır.
+type entry struct {
+	Key      string          `json:"key"`
+	StoredAt time.Time       `json:"stored_at"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// Dir, yanıtların saklandığı dizini döner.
+func Dir() (string, error) {
+	dir, err := paths.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, subDir), nil
+}
+
+// Key, kaynak adı ve uç nokta yolundan önbellek anahtarını oluşturur.
+func Key(source, endpoint string) string {
+	return source + ":" + endpoint
+}
+
+// Validator, yanıtın çağıranın beklediği biçimde olup olmadığını söyler. Biçimi tutmayan
+// yanıtlar (ör. 200 ile dönen hata nesneleri) önbelleğe alınmaz.
+type Validator func(data interface{}) bool
+
+// HasFields, yanıt verilen alanların hepsini içeren bir JSON nesnesiyse true dönen doğrulayıcıyı döner.
+func HasFields(fields ...string) Validator {
+	return func(data interface{}) bool {
+		m, ok := data.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for _, field := range fields {
+			if _, exists := m[field]; !exists {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// IsList, yanıt bir JSON dizisiyse true döner.
+func IsList(data interface{}) bool {
+	_, ok := data.([]interface{})
+	return ok
+}
+
+// Remember, anahtarın ttl süresinden yeni ve validate'ten geçen bir kaydı varsa onu döner;
+// yoksa fetch'i çağırır ve sonucu yalnızca validate'ten geçerse kaydeder. Önbellek okunamaz
+// veya yazılamazsa fetch'in sonucu yine döner.
+func Remember(key string, ttl time.Duration, fetch func() (interface{}, error), validate Validator) (interface{}, error) {
+	if disabled.Load() {
+		return fetch()
+	}
+
+	path, err := entryPath(key)
+	if err != nil {
+		return fetch()
+	}
+	if data, ok := load(path, key, ttl); ok && validate(data) {
+		return data, nil
+	}
+
+	data, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	if validate(data) {
+		_ = store(path, key, data)
+	}
+	return data, nil
+}
+
+// Clear, önbellekteki tüm yanıtları siler ve silinen kayıt sayısını döner.
+func Clear() (int, error) {
+	dir, err := Dir()
+	if err != nil {
+		return 0, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+			return removed, err
+		}
+		if strings.HasSuffix(e.Name(), ".json") {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// entryPath, anahtarın kaydedileceği dosyanın yolunu döner.
+func entryPath(key string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(dir, hex.EncodeToString(sum[:16])+".json"), nil
+}
+
+// load, kaydı okur; kayıt yoksa, süresi dolmuşsa veya başka bir anahtara aitse false döner.
+func load(path, key string, ttl time.Duration) (interface{}, bool) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var e entry
+	if err := json.Unmarshal(raw, &e); err != nil || e.Key != key || time.Since(e.StoredAt) > ttl {
+		return nil, false
+	}
+	var data interface{}
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// store, yanıtı atomik olarak kaydeder.
+func store(path, key string, data interface{}) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(entry{Key: key, StoredAt: time.Now(), Data: encoded})
+	if err != nil {
+		return err
+	}
+	dir, err := paths.Ensure(filepath.Dir(path), nil)
+	if err != nil {
+		return err
+	}
+	// Aynı anahtarı eşzamanlı yazanlar birbirinin geçici dosyasını ezmesin diye her yazım kendi dosyasını kullanır
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
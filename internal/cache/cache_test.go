@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestValidators(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate Validator
+		data     interface{}
+		want     bool
+	}{
+		{"alanlar var", HasFields("videos"), map[string]interface{}{"videos": []interface{}{}}, true},
+		{"alan eksik", HasFields("videos"), map[string]interface{}{"message": "not found"}, false},
+		{"birden fazla alan", HasFields("a", "b"), map[string]interface{}{"a": 1}, false},
+		{"nesne değil", HasFields("videos"), []interface{}{}, false},
+		{"nil", HasFields(), nil, false},
+		{"dizi", IsList, []interface{}{1.0}, true},
+		{"dizi değil", IsList, map[string]interface{}{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.validate(tt.data); got != tt.want {
+				t.Fatalf("doğrulayıcı %v için %v döndü, beklenen %v", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemember(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []interface{} // fetch'in sırayla döndüğü yanıtlar
+		wantCalls int
+	}{
+		{"geçerli yanıt önbellekten döner", []interface{}{map[string]interface{}{"ok": true}, nil}, 1},
+		{"geçersiz yanıt saklanmaz", []interface{}{map[string]interface{}{"error": "x"}, map[string]interface{}{"ok": true}}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_CACHE_HOME", t.TempDir())
+			calls := 0
+			fetch := func() (interface{}, error) {
+				calls++
+				return tt.responses[calls-1], nil
+			}
+			for range 2 {
+				if _, err := Remember("test:"+tt.name, time.Hour, fetch, HasFields("ok")); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if calls != tt.wantCalls {
+				t.Fatalf("fetch %d kez çağrıldı, beklenen %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRememberFetchError(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	want := errors.New("bağlantı hatası")
+	_, err := Remember("test:hata", time.Hour, func() (interface{}, error) { return nil, want }, IsList)
+	if !errors.Is(err, want) {
+		t.Fatalf("Remember hatası = %v, beklenen %v", err, want)
+	}
+}
+
+func TestStoreConcurrent(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	path, err := entryPath("test:eşzamanlı")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- store(path, "test:eşzamanlı", []interface{}{float64(i)})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("eşzamanlı yazım başarısız: %v", err)
+		}
+	}
+
+	if _, ok := load(path, "test:eşzamanlı", time.Hour); !ok {
+		t.Fatal("kayıt okunamadı")
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".tmp") {
+			t.Fatalf("geçici dosya kaldı: %s", e.Name())
+		}
+	}
+}
@@ -0,0 +1,334 @@
+// config paketi, kullanıcı ayarlarını kalıcı bir yapılandırma dosyasından okur ve yazar.
+//
+// Dosya TOML biçimindedir (düz anahtarlar, [bölüm] başlıkları, metin, sayı ve
+// mantıksal değerler). Komut satırı bayrakları dosyadaki değerleri geçersiz kılar.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/xeyossr/anitr-cli/internal/httpclient"
+	"github.com/xeyossr/anitr-cli/internal/naming"
+	"github.com/xeyossr/anitr-cli/internal/paths"
+)
+
+// Config, uygulamanın tüm kalıcı ayarlarını tutar.
+// Her alanın `config` etiketi dosyadaki "bölüm.anahtar" adıdır.
+type Config struct {
+	Source     string `config:"general.source"`      // Varsayılan kaynak (boşsa menüden seçilir)
+	UIMode     string `config:"general.ui_mode"`     // Arayüz modu: "tui" veya "rofi"
+	RofiFlags  string `config:"general.rofi_flags"`  // Rofi'ye aktarılacak ek parametreler
+	DisableRPC bool   `config:"general.disable_rpc"` // Discord Rich Presence kapalı mı
+	DataDir    string `config:"general.data_dir"`    // İzleme geçmişi gibi verilerin dizini (boşsa sistemin veri dizini)
+
+	Player         string `config:"player.name"`            // Oynatıcı: vlc, mpv, iina, custom
+	PlayerPath     string `config:"player.path"`            // Oynatıcının tam yolu
+	PlayerCmd      string `config:"player.command"`         // custom oynatıcının komut şablonu
+	VLCPath        string `config:"player.vlc_path"`        // VLC'nin tam yolu
+	WatchedPercent int    `config:"player.watched_percent"` // Bölümün izlendi sayılması için gereken yüzde
+	Binge          bool   `config:"player.binge"`           // Sıradaki bölümü otomatik oynat
+
+	DownloadDir         string `config:"download.dir"`             // İndirilen bölümlerin kök dizini (boşsa sistemin indirme dizini)
+	DownloadConcurrency int    `config:"download.concurrency"`     // Toplu indirmede aynı anda indirilecek bölüm sayısı
+	DownloadSubtitles   bool   `config:"download.subtitles"`       // Altyazıyı videonun yanına kaydet
+	SubtitleFormat      string `config:"download.subtitle_format"` // Altyazı biçimi: "original" veya "srt"
+	MuxSubtitles        bool   `config:"download.mux_subtitles"`   // Altyazıyı ffmpeg ile MKV'ye göm
+	NameTemplate        string `config:"download.template"`        // Bölüm dosyalarının ad şablonu (bkz. naming paketi)
+	MovieNameTemplate   string `config:"download.movie_template"`  // Film dosyalarının ad şablonu
+
+	Proxy          string `config:"network.proxy"`   // Vekil sunucu (http://, https:// veya socks5://); boşsa HTTP(S)_PROXY kullanılır
+	RequestTimeout int    `config:"network.timeout"` // API isteklerinin saniye cinsinden varsayılan süre sınırı
+
+	ThemeHighlight   string `config:"theme.highlight"`    // Seçili öğe rengi
+	ThemeNormal      string `config:"theme.normal"`       // Normal öğe rengi
+	ThemeFilter      string `config:"theme.filter"`       // Arama kutusu rengi
+	ThemeInputPrompt string `config:"theme.input_prompt"` // Giriş istemi rengi
+	ThemeInputText   string `config:"theme.input_text"`   // Giriş metni rengi
+	ThemeCursor      string `config:"theme.cursor"`       // İmleç rengi
+}
+
+// Default, varsayılan ayarları döner.
+func Default() *Config {
+	return &Config{
+		UIMode:              "tui",
+		Player:              "vlc",
+		WatchedPercent:      90,
+		DownloadConcurrency: 3,
+		DownloadSubtitles:   true,
+		SubtitleFormat:      "original",
+		NameTemplate:        naming.DefaultTemplate,
+		MovieNameTemplate:   naming.DefaultMovieTemplate,
+		RequestTimeout:      30,
+		ThemeHighlight:      "#33ccbb",
+		ThemeNormal:         "#f0f0f0",
+		ThemeFilter:         "#ff007f",
+		ThemeInputPrompt:    "#33ccbb",
+		ThemeInputText:      "#f0f0f0",
+		ThemeCursor:         "#ff007f",
+	}
+}
+
+// Path, yapılandırma dosyasının yolunu döner.
+// Linux'ta $XDG_CONFIG_HOME (yoksa ~/.config), Windows'ta %APPDATA% kullanılır.
+func Path() (string, error) {
+	dir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.toml"), nil
+}
+
+// Load, yapılandırma dosyasını okur. Dosya yoksa varsayılan ayarlar döner.
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	return LoadFile(path)
+}
+
+// LoadFile, verilen yapılandırma dosyasını okur. Dosyada olmayan ayarlar varsayılan değerini korur.
+func LoadFile(path string) (*Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("yapılandırma dosyası okunamadı: %w", err)
+	}
+
+	values, err := parseTOML(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, v := range values {
+		if err := cfg.setValue(v.key, v.value); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, v.line, err)
+		}
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save, ayarları verilen dosyaya yazar; gerekirse dizini oluşturur.
+func (c *Config) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("yapılandırma dizini oluşturulamadı: %w", err)
+	}
+	return os.WriteFile(path, []byte(c.encode()), 0644)
+}
+
+// SetFile, dosyadaki tek bir ayarı değiştirir ve güncel ayarları döner. Yalnızca ayarın
+// satırı yeniden yazılır; yorumlar ve diğer ayarlar olduğu gibi kalır. Ayar dosyada yoksa
+// bölümüne eklenir, dosya yoksa tüm ayarlarla oluşturulur. Okunamayan veya hatalı bir
+// dosya, içindeki ayarlar kaybolmasın diye değiştirilmez.
+func SetFile(path, key, value string) (*Config, error) {
+	cfg, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Set(key, value); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, cfg.Save(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("yapılandırma dosyası okunamadı: %w", err)
+	}
+
+	updated, err := setTOMLValue(string(data), key, cfg.encodeValue(key))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Keys, tüm ayar anahtarlarını dosyadaki sırayla döner.
+func Keys() []string {
+	t := reflect.TypeOf(Config{})
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		keys = append(keys, t.Field(i).Tag.Get("config"))
+	}
+	return keys
+}
+
+// Get, verilen anahtarın değerini metin olarak döner.
+func (c *Config) Get(key string) (string, error) {
+	field, err := c.field(key)
+	if err != nil {
+		return "", err
+	}
+	switch field.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(field.Bool()), nil
+	case reflect.Int:
+		return strconv.Itoa(int(field.Int())), nil
+	default:
+		return field.String(), nil
+	}
+}
+
+// Set, verilen anahtara metin olarak verilen değeri atar ve ayarları doğrular.
+func (c *Config) Set(key, value string) error {
+	field, err := c.field(key)
+	if err != nil {
+		return err
+	}
+
+	var parsed interface{} = value
+	switch field.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s için true veya false bekleniyor: %q", key, value)
+		}
+		parsed = b
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s için sayı bekleniyor: %q", key, value)
+		}
+		parsed = n
+	}
+
+	previous := *c
+	if err := c.setValue(key, parsed); err != nil {
+		return err
+	}
+	if err := c.validate(); err != nil {
+		*c = previous
+		return err
+	}
+	return nil
+}
+
+// field, anahtara karşılık gelen yapı alanını döner.
+func (c *Config) field(key string) (reflect.Value, error) {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("config") == key {
+			return v.Field(i), nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("bilinmeyen ayar: %s (kullanılabilir: %s)", key, strings.Join(Keys(), ", "))
+}
+
+// setValue, dosyadan okunan (string, int64 veya bool) değeri alanın türüne uygun şekilde atar.
+func (c *Config) setValue(key string, value interface{}) error {
+	field, err := c.field(key)
+	if err != nil {
+		return err
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s için metin bekleniyor", key)
+		}
+		field.SetString(s)
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("%s için true veya false bekleniyor", key)
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		switch n := value.(type) {
+		case int:
+			field.SetInt(int64(n))
+		case int64:
+			field.SetInt(n)
+		default:
+			return fmt.Errorf("%s için sayı bekleniyor", key)
+		}
+	}
+	return nil
+}
+
+// ValidateWatchedPercent, izlendi yüzdesinin 1 ile 100 arasında olup olmadığını kontrol eder.
+// Hem yapılandırma dosyası hem de --watched-percent bayrağı bu kontrolden geçer.
+func ValidateWatchedPercent(percent int) error {
+	if percent < 1 || percent > 100 {
+		return fmt.Errorf("1 ile 100 arasında olmalı: %d", percent)
+	}
+	return nil
+}
+
+// validate, ayarların geçerli değerlerde olup olmadığını kontrol eder.
+func (c *Config) validate() error {
+	if c.UIMode != "tui" && c.UIMode != "rofi" {
+		return fmt.Errorf("general.ui_mode \"tui\" veya \"rofi\" olmalı: %q", c.UIMode)
+	}
+	if err := ValidateWatchedPercent(c.WatchedPercent); err != nil {
+		return fmt.Errorf("player.watched_percent %w", err)
+	}
+	if c.DownloadConcurrency < 1 || c.DownloadConcurrency > 16 {
+		return fmt.Errorf("download.concurrency 1 ile 16 arasında olmalı: %d", c.DownloadConcurrency)
+	}
+	if c.SubtitleFormat != "original" && c.SubtitleFormat != "srt" {
+		return fmt.Errorf("download.subtitle_format \"original\" veya \"srt\" olmalı: %q", c.SubtitleFormat)
+	}
+	if err := naming.Validate(c.NameTemplate); err != nil {
+		return fmt.Errorf("download.template geçersiz: %w", err)
+	}
+	if err := naming.Validate(c.MovieNameTemplate); err != nil {
+		return fmt.Errorf("download.movie_template geçersiz: %w", err)
+	}
+	if c.Proxy != "" {
+		if _, err := httpclient.ParseProxy(c.Proxy); err != nil {
+			return fmt.Errorf("network.proxy geçersiz: %w", err)
+		}
+	}
+	if c.RequestTimeout < 1 || c.RequestTimeout > 300 {
+		return fmt.Errorf("network.timeout 1 ile 300 arasında olmalı: %d", c.RequestTimeout)
+	}
+	return nil
+}
+
+// encode, ayarları bölümlere ayrılmış TOML metnine çevirir.
+func (c *Config) encode() string {
+	var b strings.Builder
+	b.WriteString("# anitr-cli yapılandırma dosyası\n")
+	b.WriteString("# Komut satırı bayrakları bu dosyadaki değerleri geçersiz kılar.\n")
+
+	section := ""
+	for _, key := range Keys() {
+		sec, name, _ := strings.Cut(key, ".")
+		if sec != section {
+			section = sec
+			fmt.Fprintf(&b, "\n[%s]\n", section)
+		}
+
+		fmt.Fprintf(&b, "%s = %s\n", name, c.encodeValue(key))
+	}
+	return b.String()
+}
+
+// encodeValue, anahtarın değerini TOML değeri olarak döner; metinler tırnaklanır.
+func (c *Config) encodeValue(key string) string {
+	field, _ := c.field(key)
+	if field.Kind() == reflect.String {
+		return quoteTOML(field.String())
+	}
+	value, _ := c.Get(key)
+	return value
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetFile(t *testing.T) {
+	const base = "# kendi notlarım\n[general]\nui_mode = \"tui\"   # arayüz\n\n[player]\n# oynatıcı ayarları\nname = 'mpv'\nwatched_percent = 80#yüzde\n"
+
+	tests := []struct {
+		name    string
+		data    string // boşsa dosya oluşturulmaz
+		key     string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "metin değeri, yorum korunur",
+			data:  base,
+			key:   "general.ui_mode",
+			value: "rofi",
+			want:  "# kendi notlarım\n[general]\nui_mode = \"rofi\"   # arayüz\n\n[player]\n# oynatıcı ayarları\nname = 'mpv'\nwatched_percent = 80#yüzde\n",
+		},
+		{
+			name:  "birebir metin ve bitişik yorum",
+			data:  base,
+			key:   "player.watched_percent",
+			value: "95",
+			want:  "# kendi notlarım\n[general]\nui_mode = \"tui\"   # arayüz\n\n[player]\n# oynatıcı ayarları\nname = 'mpv'\nwatched_percent = 95#yüzde\n",
+		},
+		{
+			name:  "tırnaklı anahtar ve girinti",
+			data:  "[player]\n  \"name\"='vlc'\r\n",
+			key:   "player.name",
+			value: "mpv",
+			want:  "[player]\n  \"name\"=\"mpv\"\r\n",
+		},
+		{
+			name:  "bölümde olmayan ayar eklenir",
+			data:  base,
+			key:   "player.binge",
+			value: "true",
+			want:  "# kendi notlarım\n[general]\nui_mode = \"tui\"   # arayüz\n\n[player]\nbinge = true\n# oynatıcı ayarları\nname = 'mpv'\nwatched_percent = 80#yüzde\n",
+		},
+		{
+			name:  "olmayan bölüm eklenir",
+			data:  "[general]\nui_mode = \"tui\"",
+			key:   "network.timeout",
+			value: "10",
+			want:  "[general]\nui_mode = \"tui\"\n\n[network]\ntimeout = 10\n",
+		},
+		{name: "hatalı dosya değiştirilmez", data: "[general]\nui_mode = tui\n", key: "player.name", value: "mpv", wantErr: true},
+		{name: "geçersiz dosya değeri", data: "[player]\nwatched_percent = 0\n", key: "player.name", value: "mpv", wantErr: true},
+		{name: "geçersiz değer", data: base, key: "player.watched_percent", value: "101", wantErr: true},
+		{name: "bilinmeyen ayar", data: base, key: "player.volume", value: "50", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.toml")
+			if err := os.WriteFile(path, []byte(tt.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := SetFile(path, tt.key, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetFile hatası = %v, beklenen hata: %v", err, tt.wantErr)
+			}
+			got, _ := os.ReadFile(path)
+			if tt.wantErr {
+				if string(got) != tt.data {
+					t.Fatalf("hata sonrası dosya değişti: %q", got)
+				}
+				return
+			}
+			if string(got) != tt.want {
+				t.Fatalf("dosya %q, beklenen %q", got, tt.want)
+			}
+			if value, _ := cfg.Get(tt.key); value != tt.value {
+				t.Fatalf("%s = %q, beklenen %q", tt.key, value, tt.value)
+			}
+			if _, err := LoadFile(path); err != nil {
+				t.Fatalf("güncellenen dosya okunamadı: %v", err)
+			}
+		})
+	}
+}
+
+func TestSetFileCreates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "anitr-cli", "config.toml")
+	if _, err := SetFile(path, "player.name", "mpv"); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Default()
+	want.Player = "mpv"
+	if *cfg != *want {
+		t.Fatalf("oluşturulan dosya %+v, beklenen %+v", cfg, want)
+	}
+}
@@ -0,0 +1,345 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tomlValue, dosyadan okunan tek bir "bölüm.anahtar = değer" satırıdır.
+type tomlValue struct {
+	key   string
+	value interface{} // string, int64 veya bool
+	line  int
+}
+
+// parseTOML, yapılandırma dosyası için yeterli olan TOML alt kümesini okur:
+// [bölüm] başlıkları, anahtar = değer satırları, "temel" ve 'birebir' metinler,
+// tam sayılar, true/false ve # yorumları. Diziler ve iç içe tablolar desteklenmez.
+func parseTOML(data string) ([]tomlValue, error) {
+	var (
+		values  []tomlValue
+		section string
+	)
+
+	for i, raw := range strings.Split(data, "\n") {
+		lineNum := i + 1
+		line := strings.TrimSpace(strings.TrimSuffix(raw, "\r"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			end := strings.Index(line, "]")
+			if end == -1 || !isComment(line[end+1:]) {
+				return nil, fmt.Errorf("%d. satır: geçersiz bölüm başlığı", lineNum)
+			}
+			section = strings.TrimSpace(line[1:end])
+			if !isBareKey(section) {
+				return nil, fmt.Errorf("%d. satır: geçersiz bölüm adı: %s", lineNum, section)
+			}
+			continue
+		}
+
+		name, rest, err := parseTOMLKey(line)
+		if err != nil {
+			return nil, fmt.Errorf("%d. satır: %w", lineNum, err)
+		}
+
+		value, err := parseTOMLValue(rest)
+		if err != nil {
+			return nil, fmt.Errorf("%d. satır: %w", lineNum, err)
+		}
+
+		key := name
+		if section != "" {
+			key = section + "." + name
+		}
+		values = append(values, tomlValue{key: key, value: value, line: lineNum})
+	}
+
+	return values, nil
+}
+
+// setTOMLValue, data'da "bölüm.anahtar" ayarının değerini encoded ile değiştirir. Satırın
+// girintisi, anahtarın yazılışı ve satır sonu yorumu korunur. Anahtar yoksa bölümünün
+// başlığından hemen sonra, bölüm de yoksa dosyanın sonuna eklenir.
+func setTOMLValue(data, key, encoded string) (string, error) {
+	values, err := parseTOML(data)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(data, "\n")
+	for _, v := range values {
+		if v.key != key {
+			continue
+		}
+		line := lines[v.line-1]
+		start, end, err := valueSpan(line)
+		if err != nil {
+			return "", fmt.Errorf("%d. satır: %w", v.line, err)
+		}
+		lines[v.line-1] = line[:start] + encoded + line[end:]
+		return strings.Join(lines, "\n"), nil
+	}
+
+	section, name, _ := strings.Cut(key, ".")
+	entry := name + " = " + encoded
+	for i, raw := range lines {
+		line := strings.TrimSpace(raw)
+		if !strings.HasPrefix(line, "[") {
+			continue
+		}
+		if end := strings.Index(line, "]"); end != -1 && strings.TrimSpace(line[1:end]) == section {
+			lines = slices.Insert(lines, i+1, entry)
+			return strings.Join(lines, "\n"), nil
+		}
+	}
+
+	if data != "" && !strings.HasSuffix(data, "\n") {
+		data += "\n"
+	}
+	return data + "\n[" + section + "]\n" + entry + "\n", nil
+}
+
+// valueSpan, "anahtar = değer" satırında değerin başladığı ve bittiği bayt konumlarını döner.
+func valueSpan(line string) (int, int, error) {
+	_, rest, err := parseTOMLKey(strings.TrimSpace(line))
+	if err != nil {
+		return 0, 0, err
+	}
+	// rest, satırın sondaki boşluklar atılmış hâlinin sonekidir
+	start := len(strings.TrimRightFunc(line, unicode.IsSpace)) - len(rest)
+
+	var after string
+	switch {
+	case strings.HasPrefix(rest, `"`):
+		_, after, err = parseBasicString(rest)
+	case strings.HasPrefix(rest, "'"):
+		_, after, err = parseLiteralString(rest)
+	default:
+		_, after, err = parseBareValue(rest)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, start + len(rest) - len(after), nil
+}
+
+// parseTOMLKey, satırın başındaki (çıplak veya tırnaklı) anahtarı okur ve "=" işaretinden
+// sonraki kısmı döner.
+func parseTOMLKey(line string) (string, string, error) {
+	var (
+		name string
+		rest string
+		err  error
+	)
+	switch {
+	case strings.HasPrefix(line, `"`):
+		name, rest, err = parseBasicString(line)
+	case strings.HasPrefix(line, "'"):
+		name, rest, err = parseLiteralString(line)
+	default:
+		end := strings.IndexAny(line, " \t=")
+		if end == -1 {
+			end = len(line)
+		}
+		name, rest = line[:end], line[end:]
+		if !isBareKey(name) {
+			return "", "", fmt.Errorf("geçersiz anahtar: %s", name)
+		}
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	rest, ok := strings.CutPrefix(strings.TrimSpace(rest), "=")
+	if !ok {
+		return "", "", fmt.Errorf("\"anahtar = değer\" bekleniyor")
+	}
+	return name, strings.TrimSpace(rest), nil
+}
+
+// parseTOMLValue, satırdaki değeri çözer; değerden sonra yalnızca yorum gelebilir.
+func parseTOMLValue(s string) (interface{}, error) {
+	var (
+		value interface{}
+		rest  string
+		err   error
+	)
+	switch {
+	case strings.HasPrefix(s, `"`):
+		value, rest, err = parseBasicString(s)
+	case strings.HasPrefix(s, "'"):
+		value, rest, err = parseLiteralString(s)
+	default:
+		value, rest, err = parseBareValue(s)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !isComment(rest) {
+		return nil, fmt.Errorf("değerden sonra beklenmeyen karakterler: %s", strings.TrimSpace(rest))
+	}
+	return value, nil
+}
+
+// parseBareValue, tırnaksız bir değeri (true, false veya tam sayı) okur. Değer ilk boşlukta
+// veya yorumun başladığı # işaretinde biter.
+func parseBareValue(s string) (interface{}, string, error) {
+	end := strings.IndexAny(s, " \t#")
+	if end == -1 {
+		end = len(s)
+	}
+	bare, rest := s[:end], s[end:]
+
+	switch bare {
+	case "true":
+		return true, rest, nil
+	case "false":
+		return false, rest, nil
+	case "":
+		return nil, "", fmt.Errorf("değer eksik")
+	}
+	n, err := strconv.ParseInt(strings.ReplaceAll(bare, "_", ""), 10, 64)
+	if err != nil {
+		return nil, "", fmt.Errorf("desteklenmeyen değer: %s", bare)
+	}
+	return n, rest, nil
+}
+
+// parseBasicString, s'nin başındaki "temel" metni TOML kaçış kurallarıyla çözer ve kapanış
+// tırnağından sonraki kısmı döner.
+func parseBasicString(s string) (string, string, error) {
+	var b strings.Builder
+	for i := 1; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == '"':
+			return b.String(), s[i+1:], nil
+		case r == '\\':
+			decoded, n, err := parseEscape(s[i:])
+			if err != nil {
+				return "", "", err
+			}
+			b.WriteRune(decoded)
+			i += n
+			continue
+		case r == utf8.RuneError && size == 1:
+			return "", "", fmt.Errorf("metin geçerli UTF-8 değil")
+		case r < 0x20 && r != '\t', r == 0x7f:
+			return "", "", fmt.Errorf("metinde kaçışsız kontrol karakteri: %U", r)
+		}
+		b.WriteRune(r)
+		i += size
+	}
+	return "", "", fmt.Errorf("kapatılmamış metin")
+}
+
+// parseEscape, s'nin başındaki kaçış dizisini çözer ve kaç bayt kullandığını döner.
+// TOML'ın kaçışları (\b \t \n \f \r \" \\ \uXXXX \UXXXXXXXX) ve ESC için \e desteklenir;
+// Go'ya özgü \x, \a veya sekizli kaçışlar geçersizdir.
+func parseEscape(s string) (rune, int, error) {
+	if len(s) < 2 {
+		return 0, 0, fmt.Errorf("kapatılmamış metin")
+	}
+	switch s[1] {
+	case 'b':
+		return '\b', 2, nil
+	case 't':
+		return '\t', 2, nil
+	case 'n':
+		return '\n', 2, nil
+	case 'f':
+		return '\f', 2, nil
+	case 'r':
+		return '\r', 2, nil
+	case 'e':
+		return 0x1b, 2, nil
+	case '"':
+		return '"', 2, nil
+	case '\\':
+		return '\\', 2, nil
+	case 'u', 'U':
+		digits := 4
+		if s[1] == 'U' {
+			digits = 8
+		}
+		if len(s) < 2+digits {
+			return 0, 0, fmt.Errorf("geçersiz kaçış: %s", s)
+		}
+		n, err := strconv.ParseUint(s[2:2+digits], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(n)) {
+			return 0, 0, fmt.Errorf("geçersiz kaçış: %s", s[:2+digits])
+		}
+		return rune(n), 2 + digits, nil
+	}
+	_, size := utf8.DecodeRuneInString(s[1:])
+	return 0, 0, fmt.Errorf("geçersiz kaçış: %s", s[:1+size])
+}
+
+// parseLiteralString, s'nin başındaki 'birebir' metni okur ve kapanış tırnağından sonraki
+// kısmı döner. Birebir metinlerde kaçış yoktur.
+func parseLiteralString(s string) (string, string, error) {
+	end := strings.Index(s[1:], "'")
+	if end == -1 {
+		return "", "", fmt.Errorf("kapatılmamış metin")
+	}
+	return s[1 : end+1], s[end+2:], nil
+}
+
+// quoteTOML, metni TOML "temel" metni olarak tırnaklar. Yalnızca TOML'ın tanıdığı kaçışlar
+// kullanılır; diğer kontrol karakterleri \uXXXX olarak yazılır.
+func quoteTOML(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// isComment, değerden sonra kalan kısmın boş veya yalnızca bir yorum olup olmadığını döner.
+func isComment(s string) bool {
+	s = strings.TrimSpace(s)
+	return s == "" || strings.HasPrefix(s, "#")
+}
+
+// isBareKey, s'nin TOML'da tırnaksız yazılabilen bir anahtar (A-Z, a-z, 0-9, _ ve -) olup
+// olmadığını döner.
+func isBareKey(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
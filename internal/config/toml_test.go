@@ -0,0 +1,179 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseTOML(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []tomlValue
+		wantErr bool
+	}{
+		{
+			name:  "bölümler ve türler",
+			input: "top = 1\n[general]\nui_mode = \"tui\"\nbinge = true\n\n[player]\nwatched_percent = 1_000\n",
+			want: []tomlValue{
+				{key: "top", value: int64(1), line: 1},
+				{key: "general.ui_mode", value: "tui", line: 3},
+				{key: "general.binge", value: true, line: 4},
+				{key: "player.watched_percent", value: int64(1000), line: 7},
+			},
+		},
+		{
+			name:  "yorumlar",
+			input: "# baş\n[a] # bölüm\nx = 5 # sayı\ny = false# bitişik\nz = -3#\n",
+			want: []tomlValue{
+				{key: "a.x", value: int64(5), line: 3},
+				{key: "a.y", value: false, line: 4},
+				{key: "a.z", value: int64(-3), line: 5},
+			},
+		},
+		{
+			name:  "metin içindeki # yorum değildir",
+			input: "a = \"#ff0000\" # renk\nb = 'x # y'\n\"c#d\" = 1\n",
+			want: []tomlValue{
+				{key: "a", value: "#ff0000", line: 1},
+				{key: "b", value: "x # y", line: 2},
+				{key: "c#d", value: int64(1), line: 3},
+			},
+		},
+		{
+			name:  "tırnaklı anahtarda eşittir",
+			input: "'a=b' = 'c'\n",
+			want:  []tomlValue{{key: "a=b", value: "c", line: 1}},
+		},
+		{
+			name:  "birebir metinde kaçış yok",
+			input: `path = 'C:\Users\x'` + "\r\n",
+			want:  []tomlValue{{key: "path", value: `C:\Users\x`, line: 1}},
+		},
+		{name: "çıplak metin", input: "a = tui\n", wantErr: true},
+		{name: "yorumla bölünmüş çıplak değer", input: "a = abc#def\n", wantErr: true},
+		{name: "değer eksik", input: "a =\n", wantErr: true},
+		{name: "değer eksik, yalnız yorum", input: "a = # yorum\n", wantErr: true},
+		{name: "eşittir yok", input: "a\n", wantErr: true},
+		{name: "kapatılmamış metin", input: "a = \"abc\n", wantErr: true},
+		{name: "kapatılmamış birebir metin", input: "a = 'abc\n", wantErr: true},
+		{name: "metinden sonra çöp", input: "a = \"x\" y\n", wantErr: true},
+		{name: "sayıdan sonra çöp", input: "a = 1 2\n", wantErr: true},
+		{name: "geçersiz bölüm", input: "[a\n", wantErr: true},
+		{name: "bölümden sonra çöp", input: "[a] b\n", wantErr: true},
+		{name: "boş bölüm adı", input: "[]\n", wantErr: true},
+		{name: "boşluklu anahtar", input: "a b = 1\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTOML(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTOML(%q) hatası = %v, beklenen hata: %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("parseTOML(%q) = %#v, beklenen %#v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBasicStringEscapes(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{`"a\tb\nc"`, "a\tb\nc", false},
+		{`"\b\f\r"`, "\b\f\r", false},
+		{`"\"\\"`, `"\`, false},
+		{`"\e[0m"`, "\x1b[0m", false},
+		{`"\u00e7\U0001F600"`, "ç😀", false},
+		{`"çğü"`, "çğü", false},
+		{"\"a\tb\"", "a\tb", false},
+		{`"\x41"`, "", true},       // Go'ya özgü
+		{`"\a"`, "", true},         // Go'ya özgü
+		{`"\101"`, "", true},       // Go'ya özgü sekizli kaçış
+		{`"\'"`, "", true},         // Go'ya özgü
+		{`"\uD800"`, "", true},     // vekil (surrogate) kod noktası
+		{`"\u12"`, "", true},       // eksik basamak
+		{`"\U00110000"`, "", true}, // Unicode aralığı dışında
+		{"\"a\x01b\"", "", true},   // kaçışsız kontrol karakteri
+		{`"abc\"`, "", true},       // kapanış tırnağı kaçırılmış
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, rest, err := parseBasicString(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseBasicString(%q) hatası = %v, beklenen hata: %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && (got != tt.want || rest != "") {
+				t.Fatalf("parseBasicString(%q) = %q, %q; beklenen %q", tt.input, got, rest, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteTOMLRoundTrip(t *testing.T) {
+	tests := []string{
+		"",
+		"tui",
+		`C:\Program Files\VideoLAN\vlc.exe`,
+		`celluloid "{url}"`,
+		"satır\nsonu\ttab\r",
+		"\x1b[1m\x7f\x00",
+		"#ff0000 # yorum değil",
+		"Türkçe: ğüşıöç 😀",
+	}
+	for _, s := range tests {
+		t.Run(s, func(t *testing.T) {
+			quoted := quoteTOML(s)
+			got, err := parseTOMLValue(quoted)
+			if err != nil {
+				t.Fatalf("parseTOMLValue(%s) hatası: %v", quoted, err)
+			}
+			if got != s {
+				t.Fatalf("gidiş-dönüş %q -> %s -> %q", s, quoted, got)
+			}
+		})
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	cfg := Default()
+	cfg.PlayerCmd = `mpv --title="{title}" {url} # \x41`
+	cfg.RofiFlags = "-theme 'a b'\t\\"
+	cfg.WatchedPercent = 75
+	cfg.Binge = !cfg.Binge
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := cfg.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("kaydedilen dosya okunamadı: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, loaded) {
+		t.Fatalf("gidiş-dönüş farklı:\n%#v\n%#v", cfg, loaded)
+	}
+}
+
+func TestValidateWatchedPercent(t *testing.T) {
+	tests := []struct {
+		percent int
+		wantErr bool
+	}{
+		{0, true},
+		{1, false},
+		{90, false},
+		{100, false},
+		{101, true},
+		{-5, true},
+	}
+	for _, tt := range tests {
+		if err := ValidateWatchedPercent(tt.percent); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateWatchedPercent(%d) = %v", tt.percent, err)
+		}
+	}
+}
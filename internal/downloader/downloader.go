@@ -1,42 +1,326 @@
 package downloader
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/schollz/progressbar/v3"
+	"github.com/xeyossr/anitr-cli/internal/httpclient"
 )
 
+const (
+	// MaxRetries, geçici hatalarda yapılacak en fazla yeniden deneme sayısıdır.
+	MaxRetries = 5
+
+	// partSuffix, indirme sürerken kullanılan geçici dosyanın uzantısıdır.
+	partSuffix = ".part"
+
+	initialBackoff = time.Second
+	maxBackoff     = 30 * time.Second
+
+	// stallTimeout, indirme sırasında veri gelmeden beklenebilecek en uzun süredir.
+	stallTimeout = 60 * time.Second
+)
+
+// client, indirmelerde kullanılan HTTP istemcisidir. Büyük dosyalar için toplam süre sınırı
+// yoktur; veri akışı stallTimeout boyunca durursa istek kesilir ve withRetry ile yeniden denenir.
+// Yeniden denemeler withRetry'da yapıldığından istemci kendisi yeniden denemez.
+var client = httpclient.New(httpclient.Options{Timeout: -1, IdleTimeout: stallTimeout})
+
+// retryableError, yeniden denenebilecek geçici bir hatayı işaretler.
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+func retryable(err error) error {
+	return &retryableError{err: err}
+}
+
+// HTTPError, sunucunun başarısız bir durum koduyla yanıt verdiğini bildirir.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *HTTPError) Error() string { return "hatalı durum: " + e.Status }
+
+// IsExpired, hatanın süresi dolmuş veya geçersizleşmiş bir indirme bağlantısından
+// kaynaklanıp kaynaklanmadığını bildirir. Bu durumda bağlantı kaynaktan yeniden alınmalıdır.
+func IsExpired(err error) bool {
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+	switch httpErr.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusGone:
+		return true
+	}
+	return false
+}
+
+// progress, indirme ilerlemesinin bildirildiği hedeftir (tek ilerleme çubuğu veya kuyruk satırı).
+// Yazılan bayt sayısı Write ile eklenir.
+type progress interface {
+	io.Writer
+	SetTotal(total int64)
+	SetCurrent(n int64)
+}
+
+// barProgress, progressbar'ı progress arayüzüne uyarlar.
+type barProgress struct {
+	*progressbar.ProgressBar
+}
+
+func (b barProgress) SetTotal(total int64) { b.ChangeMax64(total) }
+func (b barProgress) SetCurrent(n int64)   { _ = b.Set64(n) }
+
+// retryFunc, her yeniden denemeden önce hata, bekleme süresi ve deneme numarasıyla çağrılır.
+type retryFunc func(err error, wait time.Duration, attempt int)
+
+// fetcher, tek bir indirmenin yaptığı tüm isteklerin (Range, bölüt, anahtar, altyazı) ortak
+// ayarlarıdır. Video sunucuları çoğu zaman kaynağın User-Agent ve Referer başlıklarını ister;
+// bunlar gönderilmezse 403 döner ve bağlantı boşuna yenilenir.
+type fetcher struct {
+	headers map[string]string // Akışla birlikte gelen başlıklar (bkz. models.Stream.Headers)
+	onRetry retryFunc
+}
+
+// newRequest, indirmenin başlıklarını taşıyan bir istek oluşturur.
+func (f *fetcher) newRequest(ctx context.Context, method, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("yeni istek oluşturulamadı: %w", err)
+	}
+	for k, v := range f.headers {
+		req.Header.Set(k, v)
+	}
+	return req, nil
+}
+
+// retry, fn'i withRetry ile indirmenin yeniden deneme bildirimiyle çalıştırır.
+func (f *fetcher) retry(ctx context.Context, fn func() error) error {
+	return withRetry(ctx, f.onRetry, fn)
+}
+
 // DownloadFile downloads a file from the given URL to the specified filepath.
-func DownloadFile(url string, filepath string) error {
-	req, err := http.NewRequest("GET", url, nil)
+//
+// Veri önce "<dosya>.part" dosyasına yazılır. Sunucu destekliyorsa yarım kalan indirme
+// Range isteğiyle kaldığı yerden sürdürülür, geçici hatalarda üstel bekleme ile yeniden
+// denenir. Boyut Content-Length ile doğrulandıktan sonra dosya asıl adına taşınır.
+//
+// Adres bir HLS (m3u8) oynatma listesiyse quality etiketine uyan varyant indirilir
+// (bkz. downloadHLS). Dosyanın kaydedildiği son yol döner; MPEG-TS bölütlerinden oluşan
+// yayınlarda uzantı ".ts" olur. headers tüm isteklere eklenir. ctx iptal edilirse indirme
+// durur ve .part dosyası sonraki denemede devam edilmek üzere bırakılır.
+func DownloadFile(ctx context.Context, url, filepath, quality string, headers map[string]string) (string, error) {
+	bar := newProgressBar(filepath)
+	f := &fetcher{headers: headers, onRetry: func(err error, wait time.Duration, attempt int) {
+		fmt.Fprintf(os.Stderr, "\n[!] %v, %s sonra yeniden denenecek (%d/%d)\n", err, wait, attempt, MaxRetries)
+	}}
+	path, err := download(ctx, f, url, filepath, quality, barProgress{bar})
 	if err != nil {
-		return fmt.Errorf("yeni istek oluşturulamadı: %w", err)
+		return "", err
 	}
+	_ = bar.Finish()
+	return path, nil
+}
+
+// download, dosyayı indirir ve tamamlanan dosyayı dizinin manifestine kaydeder.
+func download(ctx context.Context, f *fetcher, url, filepath, quality string, p progress) (string, error) {
+	path, err := fetch(ctx, f, url, filepath, quality, p)
+	if err != nil {
+		return "", err
+	}
+	// Manifest yazılamasa da indirilen dosya geçerlidir; yalnızca verify onu "kayıtsız" gösterir
+	_ = updateManifest(path, url, "")
+	return path, nil
+}
 
-	resp, err := http.DefaultClient.Do(req)
+// fetch, dosyayı .part dosyası üzerinden yeniden deneyerek indirir ve tamamlanınca asıl adına taşır.
+// Yanıt bir HLS oynatma listesi çıkarsa indirme downloadHLS ile sürdürülür.
+func fetch(ctx context.Context, f *fetcher, url, filepath, quality string, p progress) (string, error) {
+	if isPlaylistURL(url) {
+		return downloadHLS(ctx, f, url, filepath, quality, p)
+	}
+
+	partPath := filepath + partSuffix
+	err := f.retry(ctx, func() error {
+		return downloadPart(ctx, f, url, partPath, p)
+	})
+	if errors.Is(err, errPlaylist) {
+		os.Remove(partPath)
+		return downloadHLS(ctx, f, url, filepath, quality, p)
+	}
 	if err != nil {
-		return fmt.Errorf("istek yapılamadı: %w", err)
+		return "", err
+	}
+
+	if err := os.Rename(partPath, filepath); err != nil {
+		return "", fmt.Errorf("dosya taşınamadı: %w", err)
+	}
+	return filepath, nil
+}
+
+// withRetry, fn'i geçici hatalarda üstel bekleme ile en fazla MaxRetries kez yeniden dener.
+// ctx iptal edilirse beklemeden ctx'in hatası döner.
+func withRetry(ctx context.Context, onRetry retryFunc, fn func() error) error {
+	backoff := initialBackoff
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		var retryErr *retryableError
+		if !errors.As(err, &retryErr) || attempt >= MaxRetries {
+			return err
+		}
+
+		onRetry(err, backoff, attempt+1)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// downloadPart, .part dosyasını tamamlamak için tek bir istek yapar.
+// Dosyada veri varsa Range isteğiyle devam edilir; sunucu Range desteklemiyorsa baştan indirilir.
+func downloadPart(ctx context.Context, f *fetcher, url, partPath string, p progress) error {
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := f.newRequest(ctx, "GET", url)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return retryable(fmt.Errorf("istek yapılamadı: %w", err))
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("hatalı durum: %s", resp.Status)
+	var total int64 = -1
+	flags := os.O_CREATE | os.O_WRONLY
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		start, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			// Sunucu beklenmeyen bir aralık döndü, baştan indirilir
+			os.Remove(partPath)
+			return retryable(fmt.Errorf("geçersiz Content-Range: %q", resp.Header.Get("Content-Range")))
+		}
+		total = size
+		flags |= os.O_APPEND
+
+	case http.StatusOK:
+		if isPlaylistResponse(resp) {
+			return errPlaylist
+		}
+		// Sunucu Range desteklemiyor veya ilk istek; dosya baştan yazılır
+		offset = 0
+		total = resp.ContentLength
+		flags |= os.O_TRUNC
+
+	case http.StatusRequestedRangeNotSatisfiable:
+		// Dosya zaten tamamlanmış olabilir
+		_, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if ok && size == offset {
+			p.SetTotal(size)
+			p.SetCurrent(size)
+			return nil
+		}
+		os.Remove(partPath)
+		return retryable(&HTTPError{StatusCode: resp.StatusCode, Status: resp.Status})
+
+	default:
+		err := &HTTPError{StatusCode: resp.StatusCode, Status: resp.Status}
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+			return retryable(err)
+		}
+		return err
 	}
 
-	f, err := os.OpenFile(filepath, os.O_CREATE|os.O_WRONLY, 0644)
+	out, err := os.OpenFile(partPath, flags, 0644)
 	if err != nil {
 		return fmt.Errorf("dosya oluşturulamadı: %w", err)
 	}
-	defer f.Close()
+	defer out.Close()
+
+	p.SetTotal(total)
+	p.SetCurrent(offset)
+
+	written, err := io.Copy(io.MultiWriter(out, p), resp.Body)
+	if err != nil {
+		return retryable(fmt.Errorf("indirme yarıda kesildi: %w", err))
+	}
 
-	size, _ := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	if total >= 0 && offset+written != total {
+		return retryable(fmt.Errorf("eksik indirme: %d/%d bayt", offset+written, total))
+	}
+	return nil
+}
 
-	bar := progressbar.NewOptions64(size, 
+// parseContentRange, "bytes başlangıç-bitiş/toplam" veya "bytes */toplam" başlığını çözer.
+// Toplam boyut bilinmiyorsa ("*") size -1 döner.
+func parseContentRange(header string) (start, size int64, ok bool) {
+	spec, found := strings.CutPrefix(header, "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+	rng, totalStr, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, false
+	}
+
+	size = -1
+	if totalStr != "*" {
+		n, err := strconv.ParseInt(totalStr, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		size = n
+	}
+
+	if rng == "*" {
+		return 0, size, true
+	}
+	startStr, _, found := strings.Cut(rng, "-")
+	if !found {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(startStr, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, size, true
+}
+
+// newProgressBar, indirme için ilerleme çubuğu oluşturur. Boyut ilk yanıtla birlikte belirlenir.
+func newProgressBar(filepath string) *progressbar.ProgressBar {
+	bar := progressbar.NewOptions64(-1,
 		progressbar.OptionSetDescription(fmt.Sprintf("İndiriliyor: %s", filepath)),
 		progressbar.OptionSetWriter(os.Stderr),
 		progressbar.OptionShowBytes(true),
@@ -57,11 +341,5 @@ func DownloadFile(url string, filepath string) error {
 		}),
 	)
 	bar.RenderBlank()
-
-	_, err = io.Copy(io.MultiWriter(f, bar), resp.Body)
-	if err != nil {
-		return fmt.Errorf("dosyaya yazılamadı: %w", err)
-	}
-
-	return nil
+	return bar
 }
@@ -0,0 +1,142 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		header    string
+		wantStart int64
+		wantSize  int64
+		wantOK    bool
+	}{
+		{"bytes 0-99/100", 0, 100, true},
+		{"bytes 500-999/1000", 500, 1000, true},
+		{"bytes 0-0/123456789", 0, 123456789, true},
+		{"bytes 100-199/*", 100, -1, true},
+		{"bytes */1000", 0, 1000, true},
+		{"", 0, 0, false},
+		{"0-99/100", 0, 0, false},
+		{"items 0-99/100", 0, 0, false},
+		{"bytes 0-99", 0, 0, false},
+		{"bytes abc-99/100", 0, 0, false},
+		{"bytes 0-99/abc", 0, 0, false},
+		{"bytes 100/200", 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			start, size, ok := parseContentRange(tt.header)
+			if ok != tt.wantOK || (ok && (start != tt.wantStart || size != tt.wantSize)) {
+				t.Fatalf("parseContentRange(%q) = %d, %d, %v; beklenen %d, %d, %v",
+					tt.header, start, size, ok, tt.wantStart, tt.wantSize, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	permanent := errors.New("404")
+
+	tests := []struct {
+		name      string
+		errs      []error // fn'in sırayla döndüğü hatalar
+		wantErr   error
+		wantCalls int
+	}{
+		{"ilk denemede başarılı", []error{nil}, nil, 1},
+		{"kalıcı hata yeniden denenmez", []error{permanent}, permanent, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := withRetry(context.Background(), func(error, time.Duration, int) {}, func() error {
+				calls++
+				return tt.errs[calls-1]
+			})
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("withRetry hatası = %v, beklenen %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Fatalf("fn %d kez çağrıldı, beklenen %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestWithRetryCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := withRetry(ctx, func(error, time.Duration, int) { cancel() }, func() error {
+		calls++
+		return retryable(errors.New("bağlantı koptu"))
+	})
+	if !errors.Is(err, context.Canceled) || calls != 1 {
+		t.Fatalf("iptal edilen bağlamda withRetry = %v (%d çağrı)", err, calls)
+	}
+}
+
+// discardProgress, testlerde ilerlemeyi yok sayar.
+type discardProgress struct{}
+
+func (discardProgress) Write(p []byte) (int, error) { return len(p), nil }
+func (discardProgress) SetTotal(int64)              {}
+func (discardProgress) SetCurrent(int64)            {}
+
+func TestDownloadPartResume(t *testing.T) {
+	content := []byte("0123456789abcdefghij")
+	tests := []struct {
+		name    string
+		partial string // .part dosyasında önceden bulunan veri
+		ranges  bool   // sunucu Range destekliyor mu
+	}{
+		{"baştan", "", true},
+		{"kaldığı yerden", "0123456789", true},
+		{"Range desteklenmiyor", "0123456789", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRange string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Referer") != "https://example.com/" {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				gotRange = r.Header.Get("Range")
+				if !tt.ranges {
+					w.Write(content)
+					return
+				}
+				http.ServeContent(w, r, "video.mp4", time.Time{}, bytes.NewReader(content))
+			}))
+			defer srv.Close()
+
+			partPath := filepath.Join(t.TempDir(), "video.mp4"+partSuffix)
+			if tt.partial != "" {
+				if err := os.WriteFile(partPath, []byte(tt.partial), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			f := &fetcher{headers: map[string]string{"Referer": "https://example.com/"}}
+			if err := downloadPart(context.Background(), f, srv.URL, partPath, discardProgress{}); err != nil {
+				t.Fatal(err)
+			}
+			got, _ := os.ReadFile(partPath)
+			if !bytes.Equal(got, content) {
+				t.Fatalf(".part içeriği %q, beklenen %q", got, content)
+			}
+			if wantRange := tt.partial != ""; (gotRange != "") != wantRange {
+				t.Fatalf("Range başlığı = %q", gotRange)
+			}
+		})
+	}
+}
@@ -0,0 +1,590 @@
+package downloader
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// segmentDirSuffix, HLS bölütlerinin indirme sürerken tutulduğu dizinin uzantısıdır.
+	// Tamamlanan bölütler bu dizinde kaldığı için yarım kalan indirme kaldığı yerden sürer.
+	segmentDirSuffix = ".part.d"
+
+	// playlistStampFile, bölüt dizininde bölütlerin ait olduğu oynatma listesinin özetini tutar.
+	playlistStampFile = "playlist.sha256"
+
+	// segmentWorkers, bir HLS indirmesinde aynı anda indirilen bölüt sayısıdır.
+	segmentWorkers = 4
+)
+
+// errPlaylist, yanıtın bir dosya değil HLS oynatma listesi olduğunu bildirir.
+var errPlaylist = errors.New("yanıt bir HLS oynatma listesi")
+
+// variant, ana oynatma listesindeki tek bir çözünürlük seçeneğidir.
+type variant struct {
+	uri       string
+	bandwidth int
+	height    int
+	name      string
+}
+
+// segmentKey, bölütün şifreleme bilgisidir (#EXT-X-KEY).
+type segmentKey struct {
+	method string
+	uri    string
+	iv     []byte // Belirtilmemişse nil; bu durumda bölütün sıra numarası kullanılır
+}
+
+// segment, medya oynatma listesindeki tek bir bölümdür.
+type segment struct {
+	uri      string
+	sequence int64
+	key      *segmentKey
+}
+
+// mediaPlaylist, indirilecek bölütlerin listesidir.
+type mediaPlaylist struct {
+	init     string // fMP4 yayınlarda başlangıç bölütü (#EXT-X-MAP), yoksa boş
+	segments []segment
+}
+
+// isPlaylistURL, adresin yolu .m3u8 ile bitiyorsa true döner.
+func isPlaylistURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return strings.HasSuffix(strings.ToLower(u.Path), ".m3u8")
+}
+
+// isPlaylistResponse, yanıtın içerik türünden veya ilk baytlarından HLS oynatma listesi olup olmadığını anlar.
+// İlk baytlara bakabilmek için gövde tamponlu bir okuyucuyla değiştirilir.
+func isPlaylistResponse(resp *http.Response) bool {
+	contentType := strings.ToLower(resp.Header.Get("Content-Type"))
+	if strings.Contains(contentType, "mpegurl") {
+		return true
+	}
+
+	br := bufio.NewReader(resp.Body)
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{br, resp.Body}
+	head, _ := br.Peek(len("#EXTM3U"))
+	return string(head) == "#EXTM3U"
+}
+
+// downloadHLS, HLS oynatma listesini indirir. Ana listede quality etiketine uyan varyant seçilir,
+// bölütler eşzamanlı indirilip (AES-128 şifreliyse çözülerek) tek bir dosyada birleştirilir.
+// fMP4 yayınlar filepath'e, MPEG-TS yayınlar ".ts" uzantılı dosyaya yazılır; son yol döner.
+func downloadHLS(ctx context.Context, f *fetcher, playlistURL, filepath, quality string, p progress) (string, error) {
+	playlist, err := loadMediaPlaylist(ctx, f, playlistURL, quality)
+	if err != nil {
+		return "", err
+	}
+	if len(playlist.segments) == 0 {
+		return "", fmt.Errorf("oynatma listesinde bölüt yok")
+	}
+
+	finalPath := filepath
+	if playlist.init == "" {
+		finalPath = replaceExt(filepath, ".ts")
+	}
+
+	segDir := filepath + segmentDirSuffix
+	if err := prepareSegmentDir(segDir, playlist); err != nil {
+		return "", err
+	}
+
+	files, err := fetchSegments(ctx, f, playlist, segDir, p)
+	if err != nil {
+		return "", err
+	}
+
+	// Bölütler .part dosyasında birleştirilir, ardından asıl adına taşınır
+	partPath := finalPath + partSuffix
+	if err := concatFiles(partPath, files); err != nil {
+		return "", err
+	}
+	if err := os.Rename(partPath, finalPath); err != nil {
+		return "", fmt.Errorf("dosya taşınamadı: %w", err)
+	}
+	os.RemoveAll(segDir)
+	return finalPath, nil
+}
+
+// prepareSegmentDir, bölüt dizinini oluşturur. Dizindeki bölütler başka bir oynatma listesine
+// aitse (ör. yenilenen bağlantı farklı bir varyanta çıktıysa) karışmamaları için silinir.
+func prepareSegmentDir(segDir string, playlist *mediaPlaylist) error {
+	stampPath := filepath.Join(segDir, playlistStampFile)
+	stamp := playlist.fingerprint()
+	if saved, err := os.ReadFile(stampPath); err != nil || string(saved) != stamp {
+		if err := os.RemoveAll(segDir); err != nil {
+			return fmt.Errorf("eski bölütler silinemedi: %w", err)
+		}
+	}
+	if err := os.MkdirAll(segDir, 0755); err != nil {
+		return fmt.Errorf("dizin oluşturulamadı: %w", err)
+	}
+	if err := os.WriteFile(stampPath, []byte(stamp), 0644); err != nil {
+		return fmt.Errorf("oynatma listesi özeti yazılamadı: %w", err)
+	}
+	return nil
+}
+
+// fingerprint, oynatma listesinin bölüt düzenini (bölüt sayısı, sıra numaraları, yollar ve
+// şifreleme) özetler. İmzalı bağlantılar her yenilemede değiştiğinden adreslerin sorgu kısmı
+// hesaba katılmaz.
+func (pl *mediaPlaylist) fingerprint() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "init %s\n", stripQuery(pl.init))
+	for _, seg := range pl.segments {
+		fmt.Fprintf(h, "%d %s", seg.sequence, stripQuery(seg.uri))
+		if seg.key != nil {
+			fmt.Fprintf(h, " %s %s %x", seg.key.method, stripQuery(seg.key.uri), seg.key.iv)
+		}
+		fmt.Fprintln(h)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// stripQuery, adresin sorgu ve parça kısmını kaldırır.
+func stripQuery(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.RawQuery, u.Fragment = "", ""
+	return u.String()
+}
+
+// loadMediaPlaylist, oynatma listesini indirir; ana listeyse uygun varyantın medya listesini döner.
+func loadMediaPlaylist(ctx context.Context, f *fetcher, playlistURL, quality string) (*mediaPlaylist, error) {
+	body, err := fetchBytes(ctx, f, playlistURL)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(body), []byte("#EXTM3U")) {
+		return nil, fmt.Errorf("geçersiz HLS oynatma listesi")
+	}
+
+	if variants := parseMasterPlaylist(string(body), playlistURL); len(variants) > 0 {
+		v := selectVariant(variants, quality)
+		body, err = fetchBytes(ctx, f, v.uri)
+		if err != nil {
+			return nil, err
+		}
+		playlistURL = v.uri
+	}
+
+	return parseMediaPlaylist(string(body), playlistURL)
+}
+
+// parseMasterPlaylist, ana oynatma listesindeki varyantları döner. Liste bir medya listesiyse boş döner.
+func parseMasterPlaylist(data, baseURL string) []variant {
+	var (
+		variants []variant
+		pending  *variant
+	)
+	for _, line := range playlistLines(data) {
+		switch {
+		case strings.HasPrefix(line, "#EXT-X-STREAM-INF:"):
+			attrs := parseAttributes(strings.TrimPrefix(line, "#EXT-X-STREAM-INF:"))
+			v := variant{name: attrs["NAME"]}
+			v.bandwidth, _ = strconv.Atoi(attrs["BANDWIDTH"])
+			if _, h, ok := strings.Cut(attrs["RESOLUTION"], "x"); ok {
+				v.height, _ = strconv.Atoi(h)
+			}
+			pending = &v
+		case strings.HasPrefix(line, "#"):
+		case pending != nil:
+			pending.uri = resolveURL(baseURL, line)
+			variants = append(variants, *pending)
+			pending = nil
+		}
+	}
+	return variants
+}
+
+// parseMediaPlaylist, medya oynatma listesindeki bölütleri ve şifreleme bilgilerini döner.
+func parseMediaPlaylist(data, baseURL string) (*mediaPlaylist, error) {
+	playlist := &mediaPlaylist{}
+	var (
+		sequence int64
+		key      *segmentKey
+	)
+
+	for _, line := range playlistLines(data) {
+		switch {
+		case strings.HasPrefix(line, "#EXT-X-MEDIA-SEQUENCE:"):
+			sequence, _ = strconv.ParseInt(strings.TrimPrefix(line, "#EXT-X-MEDIA-SEQUENCE:"), 10, 64)
+
+		case strings.HasPrefix(line, "#EXT-X-KEY:"):
+			attrs := parseAttributes(strings.TrimPrefix(line, "#EXT-X-KEY:"))
+			switch attrs["METHOD"] {
+			case "NONE":
+				key = nil
+			case "AES-128":
+				key = &segmentKey{method: "AES-128", uri: resolveURL(baseURL, attrs["URI"])}
+				if iv := attrs["IV"]; iv != "" {
+					b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(iv, "0x"), "0X"))
+					if err != nil || len(b) != aes.BlockSize {
+						return nil, fmt.Errorf("geçersiz HLS IV değeri: %s", iv)
+					}
+					key.iv = b
+				}
+			default:
+				return nil, fmt.Errorf("desteklenmeyen HLS şifrelemesi: %s", attrs["METHOD"])
+			}
+
+		case strings.HasPrefix(line, "#EXT-X-MAP:"):
+			attrs := parseAttributes(strings.TrimPrefix(line, "#EXT-X-MAP:"))
+			if attrs["BYTERANGE"] != "" {
+				return nil, fmt.Errorf("desteklenmeyen HLS özelliği: EXT-X-MAP BYTERANGE")
+			}
+			playlist.init = resolveURL(baseURL, attrs["URI"])
+
+		case strings.HasPrefix(line, "#EXT-X-BYTERANGE:"):
+			return nil, fmt.Errorf("desteklenmeyen HLS özelliği: EXT-X-BYTERANGE")
+
+		case strings.HasPrefix(line, "#"):
+
+		default:
+			playlist.segments = append(playlist.segments, segment{
+				uri:      resolveURL(baseURL, line),
+				sequence: sequence,
+				key:      key,
+			})
+			sequence++
+		}
+	}
+	return playlist, nil
+}
+
+// selectVariant, quality etiketine (örn. "1080p") uyan varyantı seçer. Tam eşleşme yoksa
+// etiketten küçük en yüksek çözünürlük, o da yoksa en yüksek bant genişliği seçilir.
+func selectVariant(variants []variant, quality string) variant {
+	sorted := append([]variant(nil), variants...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].height != sorted[j].height {
+			return sorted[i].height > sorted[j].height
+		}
+		return sorted[i].bandwidth > sorted[j].bandwidth
+	})
+
+	if quality != "" {
+		for _, v := range sorted {
+			if v.name != "" && strings.EqualFold(v.name, quality) {
+				return v
+			}
+		}
+		if height := qualityHeight(quality); height > 0 {
+			for _, v := range sorted {
+				if v.height > 0 && v.height <= height {
+					return v
+				}
+			}
+		}
+	}
+	return sorted[0]
+}
+
+var qualityDigits = regexp.MustCompile(`\d{3,4}`)
+
+// qualityHeight, "1080p", "720" gibi bir etiketten dikey çözünürlüğü çıkarır; bulunamazsa 0 döner.
+func qualityHeight(quality string) int {
+	n, _ := strconv.Atoi(qualityDigits.FindString(quality))
+	return n
+}
+
+// fetchSegments, bölütleri segDir içine eşzamanlı indirir ve birleştirme sırasına göre dosya yollarını döner.
+// Dizinde zaten bulunan bölütler yeniden indirilmez.
+func fetchSegments(ctx context.Context, f *fetcher, playlist *mediaPlaylist, segDir string, p progress) ([]string, error) {
+	type task struct {
+		index int
+		seg   segment
+	}
+
+	var tasks []task
+	if playlist.init != "" {
+		tasks = append(tasks, task{index: 0, seg: segment{uri: playlist.init}})
+	}
+	for _, seg := range playlist.segments {
+		tasks = append(tasks, task{index: len(tasks), seg: seg})
+	}
+
+	files := make([]string, len(tasks))
+	for i := range tasks {
+		files[i] = filepath.Join(segDir, fmt.Sprintf("%06d.seg", i))
+	}
+
+	// Bayt olarak toplam boyut bilinmediğinden, tamamlanan bölütlerin ortalamasıyla tahmin edilir
+	var doneBytes, doneCount atomic.Int64
+	p.SetTotal(-1)
+	p.SetCurrent(0)
+	report := func(n int64) {
+		bytesSoFar := doneBytes.Add(n)
+		count := doneCount.Add(1)
+		p.SetTotal(bytesSoFar / count * int64(len(tasks)))
+	}
+	var existing int64
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			existing += info.Size()
+			report(info.Size())
+		}
+	}
+	p.SetCurrent(existing)
+
+	keys := &keyCache{keys: make(map[string][]byte)}
+	work := make(chan task)
+	errs := make(chan error, len(tasks))
+	var wg sync.WaitGroup
+	for i := 0; i < segmentWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range work {
+				n, err := fetchSegment(ctx, f, t.seg, files[t.index], keys, p)
+				if err != nil {
+					errs <- fmt.Errorf("%d. bölüt indirilemedi: %w", t.index, err)
+					continue
+				}
+				report(n)
+			}
+		}()
+	}
+
+	for _, t := range tasks {
+		if ctx.Err() != nil {
+			break
+		}
+		if _, err := os.Stat(files[t.index]); err == nil {
+			continue
+		}
+		work <- t
+	}
+	close(work)
+	wg.Wait()
+	close(errs)
+
+	// İndirilen bölütler dizinde kalır; sonraki denemede kaldığı yerden devam edilir
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// fetchSegment, tek bir bölütü indirir, gerekiyorsa şifresini çözer ve dest'e yazar.
+// Yarım bölüt bırakmamak için önce geçici dosyaya yazılıp taşınır. Yazılan bayt sayısı döner.
+func fetchSegment(ctx context.Context, f *fetcher, seg segment, dest string, keys *keyCache, p progress) (int64, error) {
+	data, err := fetchBytes(ctx, f, seg.uri)
+	if err != nil {
+		return 0, err
+	}
+
+	if seg.key != nil {
+		key, err := keys.get(ctx, f, seg.key.uri)
+		if err != nil {
+			return 0, err
+		}
+		iv := seg.key.iv
+		if iv == nil {
+			iv = make([]byte, aes.BlockSize)
+			binary.BigEndian.PutUint64(iv[8:], uint64(seg.sequence))
+		}
+		data, err = decryptAES128(data, key, iv)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	tmpPath := dest + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return 0, fmt.Errorf("bölüt yazılamadı: %w", err)
+	}
+	if err := os.Rename(tmpPath, dest); err != nil {
+		return 0, fmt.Errorf("bölüt taşınamadı: %w", err)
+	}
+	_, _ = p.Write(data)
+	return int64(len(data)), nil
+}
+
+// keyCache, AES anahtarlarını her bölüt için yeniden indirmemek amacıyla saklar.
+type keyCache struct {
+	mu   sync.Mutex
+	keys map[string][]byte
+}
+
+func (c *keyCache) get(ctx context.Context, f *fetcher, uri string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key, ok := c.keys[uri]; ok {
+		return key, nil
+	}
+	key, err := fetchBytes(ctx, f, uri)
+	if err != nil {
+		return nil, fmt.Errorf("şifreleme anahtarı alınamadı: %w", err)
+	}
+	if len(key) != aes.BlockSize {
+		return nil, fmt.Errorf("geçersiz şifreleme anahtarı (%d bayt)", len(key))
+	}
+	c.keys[uri] = key
+	return key, nil
+}
+
+// decryptAES128, AES-128-CBC ile şifrelenmiş bölütü çözer ve PKCS#7 dolgusunu kaldırır.
+func decryptAES128(data, key, iv []byte) ([]byte, error) {
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("şifreli bölüt boyutu geçersiz")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, data)
+
+	pad := int(out[len(out)-1])
+	if pad == 0 || pad > aes.BlockSize || pad > len(out) {
+		return nil, fmt.Errorf("bölütün şifresi çözülemedi")
+	}
+	return out[:len(out)-pad], nil
+}
+
+// fetchBytes, adresin tüm içeriğini geçici hatalarda yeniden deneyerek indirir.
+func fetchBytes(ctx context.Context, f *fetcher, rawURL string) ([]byte, error) {
+	var data []byte
+	err := f.retry(ctx, func() error {
+		req, err := f.newRequest(ctx, "GET", rawURL)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return retryable(fmt.Errorf("istek yapılamadı: %w", err))
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			err := &HTTPError{StatusCode: resp.StatusCode, Status: resp.Status}
+			if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+				return retryable(err)
+			}
+			return err
+		}
+
+		data, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return retryable(fmt.Errorf("indirme yarıda kesildi: %w", err))
+		}
+		if resp.ContentLength >= 0 && int64(len(data)) != resp.ContentLength {
+			return retryable(fmt.Errorf("eksik indirme: %d/%d bayt", len(data), resp.ContentLength))
+		}
+		return nil
+	})
+	return data, err
+}
+
+// concatFiles, dosyaları sırasıyla dest'e yazar.
+func concatFiles(dest string, files []string) error {
+	out, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("dosya oluşturulamadı: %w", err)
+	}
+
+	for _, name := range files {
+		in, err := os.Open(name)
+		if err != nil {
+			out.Close()
+			return fmt.Errorf("bölüt okunamadı: %w", err)
+		}
+		_, err = io.Copy(out, in)
+		in.Close()
+		if err != nil {
+			out.Close()
+			return fmt.Errorf("dosyaya yazılamadı: %w", err)
+		}
+	}
+	return out.Close()
+}
+
+// playlistLines, oynatma listesinin boş olmayan satırlarını döner.
+func playlistLines(data string) []string {
+	var lines []string
+	for _, line := range strings.Split(data, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// parseAttributes, `ANAHTAR=değer,ANAHTAR="tırnaklı,değer"` biçimindeki öznitelik listesini çözer.
+func parseAttributes(s string) map[string]string {
+	attrs := make(map[string]string)
+	for s != "" {
+		name, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			break
+		}
+		name = strings.TrimSpace(name)
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end == -1 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+			rest = strings.TrimPrefix(rest, ",")
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		attrs[strings.ToUpper(name)] = value
+		s = rest
+	}
+	return attrs
+}
+
+// resolveURL, oynatma listesindeki göreli adresi listenin adresine göre çözer.
+func resolveURL(base, ref string) string {
+	b, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return b.ResolveReference(r).String()
+}
+
+// replaceExt, yolun uzantısını ext ile değiştirir.
+func replaceExt(path, ext string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ext
+}
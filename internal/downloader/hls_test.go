@@ -0,0 +1,321 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMasterPlaylist(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []variant
+	}{
+		{
+			name: "varyantlar",
+			data: "#EXTM3U\n" +
+				"#EXT-X-STREAM-INF:BANDWIDTH=800000,RESOLUTION=640x360,NAME=\"360p\"\n" +
+				"360/index.m3u8\n" +
+				"#EXT-X-STREAM-INF:BANDWIDTH=5000000,RESOLUTION=1920x1080,CODECS=\"avc1.640028,mp4a.40.2\"\n" +
+				"https://cdn.example/1080/index.m3u8\n",
+			want: []variant{
+				{uri: "https://host.example/hls/360/index.m3u8", bandwidth: 800000, height: 360, name: "360p"},
+				{uri: "https://cdn.example/1080/index.m3u8", bandwidth: 5000000, height: 1080},
+			},
+		},
+		{
+			name: "CRLF ve boş satırlar",
+			data: "#EXTM3U\r\n\r\n#EXT-X-STREAM-INF:BANDWIDTH=1\r\n\r\n/abs.m3u8\r\n",
+			want: []variant{{uri: "https://host.example/abs.m3u8", bandwidth: 1}},
+		},
+		{
+			name: "medya listesi",
+			data: "#EXTM3U\n#EXTINF:10,\nseg0.ts\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseMasterPlaylist(tt.data, "https://host.example/hls/master.m3u8")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("parseMasterPlaylist = %+v, beklenen %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMediaPlaylist(t *testing.T) {
+	const base = "https://host.example/v/index.m3u8"
+	key := &segmentKey{method: "AES-128", uri: "https://host.example/v/key.bin"}
+	keyIV := &segmentKey{method: "AES-128", uri: "https://host.example/k2", iv: bytes.Repeat([]byte{0xab}, 16)}
+
+	tests := []struct {
+		name    string
+		data    string
+		want    *mediaPlaylist
+		wantErr bool
+	}{
+		{
+			name: "düz bölütler",
+			data: "#EXTM3U\n#EXT-X-MEDIA-SEQUENCE:7\n#EXTINF:10,\nseg0.ts\n#EXTINF:10,\nseg1.ts?t=1\n#EXT-X-ENDLIST\n",
+			want: &mediaPlaylist{segments: []segment{
+				{uri: "https://host.example/v/seg0.ts", sequence: 7},
+				{uri: "https://host.example/v/seg1.ts?t=1", sequence: 8},
+			}},
+		},
+		{
+			name: "şifreleme açılıp kapanır",
+			data: "#EXTM3U\n#EXT-X-KEY:METHOD=AES-128,URI=\"key.bin\"\na.ts\n" +
+				"#EXT-X-KEY:METHOD=AES-128,URI=\"/k2\",IV=0xABABABABABABABABABABABABABABABAB\nb.ts\n" +
+				"#EXT-X-KEY:METHOD=NONE\nc.ts\n",
+			want: &mediaPlaylist{segments: []segment{
+				{uri: "https://host.example/v/a.ts", sequence: 0, key: key},
+				{uri: "https://host.example/v/b.ts", sequence: 1, key: keyIV},
+				{uri: "https://host.example/v/c.ts", sequence: 2},
+			}},
+		},
+		{
+			name: "fMP4 başlangıç bölütü",
+			data: "#EXTM3U\n#EXT-X-MAP:URI=\"init.mp4\"\n#EXTINF:4,\nseg0.m4s\n",
+			want: &mediaPlaylist{init: "https://host.example/v/init.mp4", segments: []segment{
+				{uri: "https://host.example/v/seg0.m4s", sequence: 0},
+			}},
+		},
+		{name: "SAMPLE-AES", data: "#EXT-X-KEY:METHOD=SAMPLE-AES,URI=\"k\"\na.ts\n", wantErr: true},
+		{name: "kısa IV", data: "#EXT-X-KEY:METHOD=AES-128,URI=\"k\",IV=0x0102\na.ts\n", wantErr: true},
+		{name: "BYTERANGE", data: "#EXT-X-BYTERANGE:100@0\na.ts\n", wantErr: true},
+		{name: "MAP BYTERANGE", data: "#EXT-X-MAP:URI=\"i.mp4\",BYTERANGE=\"100@0\"\na.ts\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMediaPlaylist(tt.data, base)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseMediaPlaylist hatası = %v, beklenen hata: %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("parseMediaPlaylist = %+v, beklenen %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectVariant(t *testing.T) {
+	variants := []variant{
+		{uri: "360", height: 360, bandwidth: 800},
+		{uri: "1080", height: 1080, bandwidth: 5000},
+		{uri: "720", height: 720, bandwidth: 2500},
+		{uri: "720-low", height: 720, bandwidth: 1500},
+		{uri: "named", name: "Orijinal", bandwidth: 100},
+	}
+	tests := []struct {
+		quality string
+		want    string
+	}{
+		{"1080p", "1080"},
+		{"720p", "720"},
+		{"480p", "360"},
+		{"900", "720"},
+		{"240p", "1080"},
+		{"orijinal", "named"},
+		{"", "1080"},
+		{"Auto", "1080"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.quality, func(t *testing.T) {
+			if got := selectVariant(variants, tt.quality); got.uri != tt.want {
+				t.Fatalf("selectVariant(%q) = %s, beklenen %s", tt.quality, got.uri, tt.want)
+			}
+		})
+	}
+}
+
+// encryptAES128, decryptAES128'in tersidir: PKCS#7 ile doldurur ve AES-128-CBC ile şifreler.
+func encryptAES128(t *testing.T, plain, key, iv []byte) []byte {
+	t.Helper()
+	pad := aes.BlockSize - len(plain)%aes.BlockSize
+	data := append(append([]byte(nil), plain...), bytes.Repeat([]byte{byte(pad)}, pad)...)
+	return encryptBlocks(t, data, key, iv)
+}
+
+// encryptBlocks, blok katı uzunluktaki veriyi dolgu eklemeden şifreler.
+func encryptBlocks(t *testing.T, data, key, iv []byte) []byte {
+	t.Helper()
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make([]byte, len(data))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(out, data)
+	return out
+}
+
+func TestDecryptAES128(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	iv := []byte("fedcba9876543210")
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []byte
+		wantErr bool
+	}{
+		{"kısa içerik", encryptAES128(t, []byte("merhaba"), key, iv), []byte("merhaba"), false},
+		{"blok katı içerik", encryptAES128(t, bytes.Repeat([]byte("x"), 32), key, iv), bytes.Repeat([]byte("x"), 32), false},
+		{"boş içerik", encryptAES128(t, nil, key, iv), []byte{}, false},
+		{"blok katı olmayan boyut", make([]byte, 20), nil, true},
+		{"veri yok", nil, nil, true},
+		{"sıfır dolgu", encryptBlocks(t, make([]byte, 16), key, iv), nil, true},
+		{"bloktan büyük dolgu", encryptBlocks(t, bytes.Repeat([]byte{17}, 16), key, iv), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decryptAES128(tt.data, key, iv)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decryptAES128 hatası = %v, beklenen hata: %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !bytes.Equal(got, tt.want) {
+				t.Fatalf("decryptAES128 = %q, beklenen %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownloadHLS(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	ivFor := func(sequence uint64) []byte {
+		iv := make([]byte, aes.BlockSize)
+		binary.BigEndian.PutUint64(iv[8:], sequence)
+		return iv
+	}
+	files := map[string][]byte{
+		"/master.m3u8": []byte("#EXTM3U\n#EXT-X-STREAM-INF:BANDWIDTH=1,RESOLUTION=1280x720\n720/index.m3u8\n" +
+			"#EXT-X-STREAM-INF:BANDWIDTH=2,RESOLUTION=1920x1080\n1080/index.m3u8\n"),
+		"/720/index.m3u8": []byte("#EXTM3U\n#EXT-X-MEDIA-SEQUENCE:3\n#EXT-X-KEY:METHOD=AES-128,URI=\"/key\"\n" +
+			"#EXTINF:1,\na.ts\n#EXTINF:1,\nb.ts\n#EXT-X-KEY:METHOD=NONE\n#EXTINF:1,\nc.ts\n#EXT-X-ENDLIST\n"),
+		"/key":             key,
+		"/720/a.ts":        encryptAES128(t, []byte("AAAA"), key, ivFor(3)),
+		"/720/b.ts":        encryptAES128(t, []byte("BBBB"), key, ivFor(4)),
+		"/720/c.ts":        []byte("CCCC"),
+		"/1080/index.m3u8": []byte("#EXTM3U\n#EXTINF:1,\nwrong.ts\n"),
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != "anitr-test" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		data, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "bolum.mp4")
+	f := &fetcher{headers: map[string]string{"User-Agent": "anitr-test"}}
+	path, err := downloadHLS(context.Background(), f, srv.URL+"/master.m3u8", dest, "720p", discardProgress{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(path, "bolum.ts") {
+		t.Fatalf("MPEG-TS yayın için yol %s", path)
+	}
+	got, _ := os.ReadFile(path)
+	if string(got) != "AAAABBBBCCCC" {
+		t.Fatalf("birleştirilen içerik %q", got)
+	}
+	if _, err := os.Stat(dest + segmentDirSuffix); !os.IsNotExist(err) {
+		t.Fatal("bölüt dizini silinmedi")
+	}
+}
+
+func TestPrepareSegmentDir(t *testing.T) {
+	base := &mediaPlaylist{segments: []segment{
+		{uri: "https://cdn.example/v/seg0.ts?token=a", sequence: 0},
+		{uri: "https://cdn.example/v/seg1.ts?token=a", sequence: 1},
+	}}
+
+	tests := []struct {
+		name     string
+		refresh  *mediaPlaylist // Yenilenen bağlantıdan gelen oynatma listesi
+		wantKept bool           // Eski bölütler korunmalı mı
+	}{
+		{
+			name: "yalnızca imza değişti",
+			refresh: &mediaPlaylist{segments: []segment{
+				{uri: "https://cdn.example/v/seg0.ts?token=b", sequence: 0},
+				{uri: "https://cdn.example/v/seg1.ts?token=b", sequence: 1},
+			}},
+			wantKept: true,
+		},
+		{
+			name: "bölüt sayısı değişti",
+			refresh: &mediaPlaylist{segments: []segment{
+				{uri: "https://cdn.example/v/seg0.ts", sequence: 0},
+			}},
+		},
+		{
+			name: "farklı varyant",
+			refresh: &mediaPlaylist{segments: []segment{
+				{uri: "https://cdn.example/v720/seg0.ts", sequence: 0},
+				{uri: "https://cdn.example/v720/seg1.ts", sequence: 1},
+			}},
+		},
+		{
+			name: "şifreleme eklendi",
+			refresh: &mediaPlaylist{segments: []segment{
+				{uri: "https://cdn.example/v/seg0.ts", sequence: 0, key: &segmentKey{method: "AES-128", uri: "https://cdn.example/key"}},
+				{uri: "https://cdn.example/v/seg1.ts", sequence: 1, key: &segmentKey{method: "AES-128", uri: "https://cdn.example/key"}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segDir := filepath.Join(t.TempDir(), "video.mp4"+segmentDirSuffix)
+			if err := prepareSegmentDir(segDir, base); err != nil {
+				t.Fatal(err)
+			}
+			stale := filepath.Join(segDir, "000000.seg")
+			if err := os.WriteFile(stale, []byte("eski"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := prepareSegmentDir(segDir, tt.refresh); err != nil {
+				t.Fatal(err)
+			}
+			_, err := os.Stat(stale)
+			if kept := err == nil; kept != tt.wantKept {
+				t.Fatalf("bölüt korundu = %v, beklenen %v", kept, tt.wantKept)
+			}
+		})
+	}
+}
+
+func TestPrepareSegmentDirWithoutStamp(t *testing.T) {
+	// Özeti olmayan (eski sürümün bıraktığı) dizindeki bölütlerin hangi listeye ait olduğu bilinmez
+	segDir := filepath.Join(t.TempDir(), "video.mp4"+segmentDirSuffix)
+	if err := os.MkdirAll(segDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	stale := filepath.Join(segDir, "000000.seg")
+	if err := os.WriteFile(stale, []byte("eski"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := prepareSegmentDir(segDir, &mediaPlaylist{segments: []segment{{uri: "https://cdn.example/seg0.ts"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(stale); err == nil {
+		t.Fatal("özeti olmayan dizindeki bölüt silinmedi")
+	}
+}
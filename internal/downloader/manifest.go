@@ -0,0 +1,300 @@
+package downloader
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xeyossr/anitr-cli/internal/httpclient"
+)
+
+// ManifestFile, her indirme dizininde tamamlanan dosyaların kaydının tutulduğu dosyanın adıdır.
+const ManifestFile = ".anitr-manifest.json"
+
+// manifestMu, aynı anda biten indirmelerin manifest dosyasını birlikte yazmasını engeller.
+var manifestMu sync.Mutex
+
+// ManifestEntry, tamamlanmış bir indirmenin kaydıdır.
+type ManifestEntry struct {
+	Size         int64     `json:"size"`          // Dosyanın indirme bittiğindeki boyutu
+	URL          string    `json:"url,omitempty"` // İndirildiği adres
+	DownloadedAt time.Time `json:"downloaded_at"`
+}
+
+// Manifest, bir dizindeki tamamlanmış indirmeleri dosya adına göre tutar.
+type Manifest struct {
+	Files map[string]ManifestEntry `json:"files"`
+}
+
+// readManifest, dizindeki manifest dosyasını okur. Dosya yoksa boş bir manifest döner.
+func readManifest(dir string) (*Manifest, error) {
+	m := &Manifest{Files: make(map[string]ManifestEntry)}
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("manifest bozuk (%s): %w", dir, err)
+	}
+	if m.Files == nil {
+		m.Files = make(map[string]ManifestEntry)
+	}
+	return m, nil
+}
+
+// write, manifesti dizine atomik olarak yazar.
+func (m *Manifest) write(dir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, ManifestFile)
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// updateManifest, path dosyasını (güncel boyutuyla) bulunduğu dizinin manifestine ekler.
+// replaced boş değilse o dosyanın kaydı silinir (örn. MKV'ye gömülen eski video).
+func updateManifest(path, url, replaced string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	manifestMu.Lock()
+	defer manifestMu.Unlock()
+
+	dir := filepath.Dir(path)
+	m, err := readManifest(dir)
+	if err != nil {
+		return err
+	}
+	if replaced != "" {
+		if prev, ok := m.Files[filepath.Base(replaced)]; ok && url == "" {
+			url = prev.URL
+		}
+		delete(m.Files, filepath.Base(replaced))
+	}
+	m.Files[filepath.Base(path)] = ManifestEntry{Size: info.Size(), URL: url, DownloadedAt: time.Now()}
+	return m.write(dir)
+}
+
+// forgetManifest, dosyanın kaydını manifestten siler.
+func forgetManifest(path string) error {
+	manifestMu.Lock()
+	defer manifestMu.Unlock()
+
+	dir := filepath.Dir(path)
+	m, err := readManifest(dir)
+	if err != nil {
+		return err
+	}
+	if _, ok := m.Files[filepath.Base(path)]; !ok {
+		return nil
+	}
+	delete(m.Files, filepath.Base(path))
+	return m.write(dir)
+}
+
+// Existing, indirilecek bir dosyanın diskteki durumudur.
+type Existing int
+
+const (
+	ExistingNone       Existing = iota // Dosya yok
+	ExistingComplete                   // Dosya tamamen indirilmiş
+	ExistingIncomplete                 // Dosya var ama boyutu beklenenden farklı (kesik veya başka bir dosya)
+	ExistingUnknown                    // Dosya var ama sunucudaki boyut öğrenilemediği için doğrulanamadı
+)
+
+// CheckExisting, path için daha önce tamamlanmış bir indirme olup olmadığını kontrol eder.
+// HLS (.ts) veya altyazısı gömülmüş (.mkv) hâlleri de aranır; bulunan dosyanın yolu döner.
+// Dosya manifestte kayıtlıysa boyutu manifestle, değilse url'ye yapılan HEAD isteğiyle karşılaştırılır.
+// headers sunucuya yapılan isteğe eklenir.
+func CheckExisting(ctx context.Context, path, url string, headers map[string]string) (Existing, string, error) {
+	manifestMu.Lock()
+	m, err := readManifest(filepath.Dir(path))
+	manifestMu.Unlock()
+	if err != nil {
+		return ExistingNone, "", err
+	}
+
+	for _, candidate := range []string{path, replaceExt(path, ".ts"), replaceExt(path, ".mkv")} {
+		info, err := os.Stat(candidate)
+		if err != nil {
+			continue
+		}
+		if entry, ok := m.Files[filepath.Base(candidate)]; ok {
+			if entry.Size == info.Size() {
+				return ExistingComplete, candidate, nil
+			}
+			return ExistingIncomplete, candidate, nil
+		}
+		if candidate != path {
+			continue
+		}
+
+		// Manifestte yoksa (eski sürümle indirilmiş olabilir) sunucudaki boyutla karşılaştırılır.
+		// Oynatma listelerinde adresin boyutu videonunkiyle aynı olmadığından karşılaştırma yapılamaz.
+		if isPlaylistURL(url) {
+			return ExistingUnknown, candidate, nil
+		}
+		size, err := RemoteSize(ctx, url, headers)
+		if err != nil {
+			return ExistingUnknown, candidate, nil
+		}
+		if size == info.Size() {
+			_ = updateManifest(candidate, url, "")
+			return ExistingComplete, candidate, nil
+		}
+		return ExistingIncomplete, candidate, nil
+	}
+	return ExistingNone, "", nil
+}
+
+// RemoteSize, adresteki dosyanın boyutunu HEAD isteğiyle öğrenir. Sunucu HEAD'i desteklemiyorsa
+// tek baytlık bir Range isteğinin Content-Range başlığı kullanılır. headers her iki isteğe de eklenir.
+func RemoteSize(ctx context.Context, url string, headers map[string]string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, httpclient.DefaultTimeout)
+	defer cancel()
+
+	f := &fetcher{headers: headers}
+	req, err := f.newRequest(ctx, "HEAD", url)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err == nil {
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK && resp.ContentLength >= 0 {
+			return resp.ContentLength, nil
+		}
+	}
+
+	req, err = f.newRequest(ctx, "GET", url)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Range", "bytes=0-0")
+	resp, err = client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("istek yapılamadı: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusPartialContent {
+		if _, size, ok := parseContentRange(resp.Header.Get("Content-Range")); ok && size >= 0 {
+			return size, nil
+		}
+	}
+	if resp.StatusCode == http.StatusOK && resp.ContentLength >= 0 {
+		return resp.ContentLength, nil
+	}
+	return 0, fmt.Errorf("dosya boyutu öğrenilemedi: %s", resp.Status)
+}
+
+// Remove, daha önce indirilmiş dosyayı ve manifest kaydını siler (yeniden indirmeden önce).
+func Remove(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return forgetManifest(path)
+}
+
+// Problem, Verify'ın bulduğu tek bir sorundur.
+type Problem struct {
+	Path   string
+	Kind   string // "kesik", "eksik", "yarım" veya "kayıtsız"
+	Detail string
+}
+
+// videoExts, manifestte kaydı olmadığında bildirilen video uzantılarıdır.
+var videoExts = map[string]bool{".mp4": true, ".mkv": true, ".ts": true}
+
+// Verify, dir altındaki tüm manifestleri tarar ve sorunlu dosyaları döner: boyutu kayıttan
+// farklı (kesik) veya silinmiş (eksik) dosyalar, yarım kalmış .part dosyaları ve hiçbir
+// manifestte kaydı olmayan video dosyaları.
+func Verify(dir string) ([]Problem, int, error) {
+	var (
+		problems []Problem
+		checked  int
+	)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		m, err := readManifest(path)
+		if err != nil {
+			problems = append(problems, Problem{Path: filepath.Join(path, ManifestFile), Kind: "bozuk", Detail: err.Error()})
+			m = &Manifest{Files: map[string]ManifestEntry{}}
+		}
+
+		names := make([]string, 0, len(m.Files))
+		for name := range m.Files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			entry := m.Files[name]
+			filePath := filepath.Join(path, name)
+			checked++
+
+			info, err := os.Stat(filePath)
+			if err != nil {
+				problems = append(problems, Problem{Path: filePath, Kind: "eksik", Detail: "dosya bulunamadı"})
+				continue
+			}
+			if info.Size() != entry.Size {
+				problems = append(problems, Problem{
+					Path:   filePath,
+					Kind:   "kesik",
+					Detail: fmt.Sprintf("%s, beklenen %s", formatBytes(info.Size()), formatBytes(entry.Size)),
+				})
+			}
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				continue
+			}
+			name := e.Name()
+			switch {
+			case strings.HasSuffix(name, partSuffix):
+				problems = append(problems, Problem{Path: filepath.Join(path, name), Kind: "yarım", Detail: "indirme tamamlanmamış"})
+			case videoExts[strings.ToLower(filepath.Ext(name))]:
+				if _, ok := m.Files[name]; !ok {
+					problems = append(problems, Problem{Path: filepath.Join(path, name), Kind: "kayıtsız", Detail: "manifestte kaydı yok"})
+				}
+			}
+		}
+
+		// Yarım HLS bölüt dizinlerinin içine girilmez
+		if strings.HasSuffix(path, segmentDirSuffix) {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return problems, checked, err
+}
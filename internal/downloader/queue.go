@@ -0,0 +1,387 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultConcurrency, kuyrukta aynı anda çalışan varsayılan indirme sayısıdır.
+const DefaultConcurrency = 3
+
+// Status, kuyruktaki bir işin sonucudur.
+type Status int
+
+const (
+	StatusSucceeded Status = iota // İndirme tamamlandı
+	StatusFailed                  // İndirme hata ile sonlandı
+	StatusSkipped                 // İndirme hiç başlatılmadı (örn. bağlantı alınamadı)
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusSucceeded:
+		return "tamamlandı"
+	case StatusFailed:
+		return "başarısız"
+	default:
+		return "atlandı"
+	}
+}
+
+// Job, kuyruktaki tek bir indirme işidir.
+type Job struct {
+	ID      string // Kalıcı kuyruktaki kaydın ID'si (kaydedilmeyen işlerde boş)
+	Name    string // Ekranda gösterilecek ad (örn. bölüm başlığı)
+	URL     string // İndirilecek dosyanın adresi
+	Path    string // Dosyanın kaydedileceği yol; indirme bitince son yol (örn. ".ts" uzantılı) yazılır
+	Quality string // HLS oynatma listelerinde seçilecek çözünürlük etiketi
+
+	// Headers, tüm isteklere (Range, bölüt, anahtar, altyazı) eklenen başlıklardır (User-Agent, Referer vb.)
+	Headers map[string]string
+
+	SubtitleURL  string // Videoyla birlikte kaydedilecek altyazı (boşsa altyazı indirilmez)
+	SubtitleLang string // Altyazının dil kodu (örn. "tr")
+
+	// Refresh, bağlantının süresi dolmuşsa (bkz. IsExpired) yenisini döner. Boşsa bağlantı yenilenmez.
+	Refresh func(ctx context.Context) (string, error)
+}
+
+// Result, bir işin sonucunu tutar.
+type Result struct {
+	Job    Job
+	Status Status
+	Err    error // Başarısız veya atlanan işlerde nedeni
+	Warn   error // Video indirildiği hâlde oluşan sorun (örn. altyazı indirilemedi)
+}
+
+// Summary, kuyruk bittiğinde tüm işlerin sonuçlarını eklendikleri sırayla tutar.
+type Summary struct {
+	Results []Result
+}
+
+// Count, verilen durumdaki iş sayısını döner.
+func (s Summary) Count(status Status) int {
+	n := 0
+	for _, r := range s.Results {
+		if r.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Print, sonuçların özetini yazdırır.
+func (s Summary) Print(w io.Writer) {
+	fmt.Fprintf(w, "\nİndirme özeti: %d tamamlandı, %d başarısız, %d atlandı\n",
+		s.Count(StatusSucceeded), s.Count(StatusFailed), s.Count(StatusSkipped))
+	for _, r := range s.Results {
+		switch {
+		case r.Status != StatusSucceeded:
+			fmt.Fprintf(w, "  [%s] %s: %v\n", r.Status, r.Job.Name, r.Err)
+		case r.Warn != nil:
+			fmt.Fprintf(w, "  [uyarı] %s: %v\n", r.Job.Name, r.Warn)
+		}
+	}
+}
+
+// Queue, indirmeleri belirli bir eşzamanlılık sınırıyla paralel çalıştırır.
+// Her etkin iş için bir ilerleme satırı ve tüm kuyruk için bir toplam satırı gösterilir;
+// bir işin başarısız olması diğerlerini etkilemez.
+type Queue struct {
+	concurrency int
+	out         io.Writer
+
+	// Subtitles, işlerin altyazılarının nasıl kaydedileceğini belirler.
+	Subtitles SubtitleOptions
+
+	// OnFinish, her iş bittiğinde (yenilenmiş bağlantısıyla birlikte) çağrılır; örn. Store.Finish.
+	OnFinish func(job Job, err error)
+
+	jobs    []Job
+	slots   []int // jobs[i] işinin results içindeki yeri
+	results []Result
+}
+
+// NewQueue, en fazla concurrency indirmeyi aynı anda çalıştıran bir kuyruk oluşturur.
+func NewQueue(concurrency int) *Queue {
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+	return &Queue{concurrency: concurrency, out: os.Stderr}
+}
+
+// Add, kuyruğa bir indirme işi ekler.
+func (q *Queue) Add(job Job) {
+	q.jobs = append(q.jobs, job)
+	q.slots = append(q.slots, len(q.results))
+	q.results = append(q.results, Result{Job: job})
+}
+
+// Skip, indirilemeyecek bir işi nedeniyle birlikte özete ekler.
+func (q *Queue) Skip(job Job, reason error) {
+	q.results = append(q.results, Result{Job: job, Status: StatusSkipped, Err: reason})
+}
+
+// Len, kuyruğa eklenen (atlananlar hariç) iş sayısını döner.
+func (q *Queue) Len() int {
+	return len(q.jobs)
+}
+
+// Run, kuyruktaki tüm işleri çalıştırır ve hepsi bitince özeti döner. ctx iptal edilirse
+// süren indirmeler durur ve başlatılmamış işler atlanmış olarak özete yazılır.
+func (q *Queue) Run(ctx context.Context) Summary {
+	r := newRenderer(q.out, len(q.jobs))
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < q.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range work {
+				job := q.jobs[idx]
+				line := r.start(job.Name)
+				f := &fetcher{headers: job.Headers, onRetry: func(err error, wait time.Duration, attempt int) {
+					line.setNote(fmt.Sprintf("yeniden deneniyor (%d/%d): %v", attempt, MaxRetries, err))
+				}}
+				path, err := download(ctx, f, job.URL, job.Path, job.Quality, line)
+				if IsExpired(err) && job.Refresh != nil {
+					line.setNote("bağlantı yenileniyor")
+					url, refreshErr := job.Refresh(ctx)
+					if refreshErr != nil {
+						err = fmt.Errorf("%w (bağlantı yenilenemedi: %v)", err, refreshErr)
+					} else {
+						job.URL = url
+						line.setNote("")
+						path, err = download(ctx, f, job.URL, job.Path, job.Quality, line)
+					}
+				}
+				var warn error
+				if err == nil {
+					job.Path = path
+					if job.SubtitleURL != "" {
+						line.setNote("altyazı indiriliyor")
+						job.Path, warn = AttachSubtitle(ctx, job.Path, job.SubtitleURL, job.SubtitleLang, job.Headers, q.Subtitles)
+					}
+				}
+				r.finish(line, err, warn)
+				if q.OnFinish != nil {
+					q.OnFinish(job, err)
+				}
+
+				res := &q.results[q.slots[idx]]
+				res.Job, res.Warn = job, warn
+				if err != nil {
+					res.Status, res.Err = StatusFailed, err
+				}
+			}
+		}()
+	}
+
+	stop := make(chan struct{})
+	go r.loop(stop)
+
+	for idx := range q.jobs {
+		if ctx.Err() != nil {
+			res := &q.results[q.slots[idx]]
+			res.Status, res.Err = StatusSkipped, ctx.Err()
+			continue
+		}
+		work <- idx
+	}
+	close(work)
+	wg.Wait()
+
+	close(stop)
+	r.draw()
+
+	return Summary{Results: q.results}
+}
+
+// jobLine, etkin bir işin ilerleme satırıdır; progress arayüzünü uygular.
+type jobLine struct {
+	name    string
+	total   atomic.Int64
+	current atomic.Int64
+	onWrite func(n int64)
+
+	mu   sync.Mutex
+	note string
+}
+
+func (l *jobLine) Write(p []byte) (int, error) {
+	l.current.Add(int64(len(p)))
+	l.onWrite(int64(len(p)))
+	return len(p), nil
+}
+
+func (l *jobLine) SetTotal(total int64) { l.total.Store(total) }
+
+func (l *jobLine) SetCurrent(n int64) {
+	prev := l.current.Swap(n)
+	l.onWrite(n - prev)
+}
+
+func (l *jobLine) setNote(note string) {
+	l.mu.Lock()
+	l.note = note
+	l.mu.Unlock()
+}
+
+func (l *jobLine) String() string {
+	l.mu.Lock()
+	note := l.note
+	l.mu.Unlock()
+
+	current, total := l.current.Load(), l.total.Load()
+	s := fmt.Sprintf("%-30s %s", truncate(l.name, 30), progressText(current, total))
+	if note != "" {
+		s += "  " + note
+	}
+	return s
+}
+
+// renderer, etkin işlerin satırlarını ve toplam satırını terminalde yerinde günceller.
+type renderer struct {
+	out   io.Writer
+	total int
+
+	downloaded atomic.Int64
+
+	mu     sync.Mutex
+	active []*jobLine
+	done   int
+	failed int
+	drawn  int // Son çizimde yazılan satır sayısı
+}
+
+func newRenderer(out io.Writer, total int) *renderer {
+	return &renderer{out: out, total: total}
+}
+
+func (r *renderer) start(name string) *jobLine {
+	line := &jobLine{name: name}
+	line.total.Store(-1)
+	line.onWrite = func(n int64) { r.downloaded.Add(n) }
+
+	r.mu.Lock()
+	r.active = append(r.active, line)
+	r.mu.Unlock()
+	return line
+}
+
+// finish, işi etkin satırlardan çıkarır ve sonucunu kalıcı bir satır olarak yazdırır.
+func (r *renderer) finish(line *jobLine, err, warn error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, l := range r.active {
+		if l == line {
+			r.active = append(r.active[:i], r.active[i+1:]...)
+			break
+		}
+	}
+	r.done++
+
+	r.clear()
+	if err != nil {
+		r.failed++
+		fmt.Fprintf(r.out, "[!] %s: %v\n", line.name, err)
+	} else {
+		fmt.Fprintf(r.out, "✓ %s\n", line.name)
+	}
+	if warn != nil {
+		fmt.Fprintf(r.out, "[!] %s: %v\n", line.name, warn)
+	}
+	r.render()
+}
+
+func (r *renderer) loop(stop <-chan struct{}) {
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			r.draw()
+		}
+	}
+}
+
+func (r *renderer) draw() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clear()
+	r.render()
+}
+
+// clear, son çizilen satırları siler. r.mu tutulurken çağrılmalıdır.
+func (r *renderer) clear() {
+	for ; r.drawn > 0; r.drawn-- {
+		fmt.Fprint(r.out, "\x1b[1A\x1b[2K")
+	}
+}
+
+// render, etkin satırları ve toplam satırını yazar. r.mu tutulurken çağrılmalıdır.
+func (r *renderer) render() {
+	var b strings.Builder
+	for _, l := range r.active {
+		b.WriteString(l.String())
+		b.WriteByte('\n')
+	}
+	fmt.Fprintf(&b, "Toplam: %d/%d bölüm, %s indirildi", r.done, r.total, formatBytes(r.downloaded.Load()))
+	if r.failed > 0 {
+		fmt.Fprintf(&b, ", %d başarısız", r.failed)
+	}
+	b.WriteByte('\n')
+
+	fmt.Fprint(r.out, b.String())
+	r.drawn = len(r.active) + 1
+}
+
+// progressText, "[====>     ]  45% 120.3 MB/260.0 MB" biçiminde ilerleme metni döner.
+// Toplam boyut bilinmiyorsa yalnızca indirilen miktar gösterilir.
+func progressText(current, total int64) string {
+	const width = 20
+	if total <= 0 {
+		return fmt.Sprintf("[%s] %s", strings.Repeat("?", width), formatBytes(current))
+	}
+
+	filled := int(current * width / total)
+	filled = min(max(filled, 0), width)
+	bar := strings.Repeat("=", filled)
+	if filled < width {
+		bar += ">" + strings.Repeat(" ", width-filled-1)
+	}
+	return fmt.Sprintf("[%s] %3d%% %s/%s", bar, current*100/total, formatBytes(current), formatBytes(total))
+}
+
+// formatBytes, bayt sayısını okunabilir biçime çevirir.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// truncate, metni en fazla n karaktere kısaltır.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
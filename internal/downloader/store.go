@@ -0,0 +1,263 @@
+package downloader
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// StoreFile, indirme kuyruğunun veri dizininde saklandığı dosyanın adıdır.
+const StoreFile = "downloads.json"
+
+// RecordStatus, kalıcı bir indirme kaydının durumudur.
+type RecordStatus string
+
+const (
+	RecordPending   RecordStatus = "bekliyor"   // Eklendi ama henüz tamamlanmadı (program kapanmış olabilir)
+	RecordCompleted RecordStatus = "tamamlandı" // Dosya indirildi
+	RecordFailed    RecordStatus = "başarısız"  // Son deneme hata ile sonlandı
+)
+
+// Origin, süresi dolan bir indirme bağlantısının kaynaktan yeniden alınması için gereken bilgilerdir.
+type Origin struct {
+	Source         string `json:"source"`                    // Kaynağın adı
+	AnimeID        int    `json:"anime_id,omitempty"`        // Sayısal anime ID'si
+	Slug           string `json:"slug,omitempty"`            // Anime slug'ı
+	IsMovie        bool   `json:"is_movie,omitempty"`        // İçerik film mi
+	FansubID       string `json:"fansub_id,omitempty"`       // Seçilen fansub
+	Season         int    `json:"season,omitempty"`          // Sezon numarası
+	Episode        int    `json:"episode,omitempty"`         // Sezon içindeki bölüm numarası
+	AbsoluteNumber int    `json:"absolute_number,omitempty"` // Tüm sezonlar boyunca bölüm sırası
+	Quality        string `json:"quality,omitempty"`         // Seçilen çözünürlük etiketi
+	SubtitleURL    string `json:"subtitle_url,omitempty"`    // Videoyla birlikte kaydedilecek altyazı
+	SubtitleLang   string `json:"subtitle_lang,omitempty"`   // Altyazının dil kodu
+
+	Headers map[string]string `json:"headers,omitempty"` // Video sunucusunun istediği başlıklar (User-Agent, Referer vb.)
+}
+
+// Record, kalıcı indirme kuyruğundaki tek bir iştir.
+type Record struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	URL       string       `json:"url"`
+	Path      string       `json:"path"`
+	Status    RecordStatus `json:"status"`
+	Error     string       `json:"error,omitempty"`
+	Origin    Origin       `json:"origin"`
+	AddedAt   time.Time    `json:"added_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// Job, kaydı kuyrukta çalıştırılabilecek bir işe çevirir.
+func (r Record) Job() Job {
+	return Job{
+		ID:           r.ID,
+		Name:         r.Name,
+		URL:          r.URL,
+		Path:         r.Path,
+		Quality:      r.Origin.Quality,
+		Headers:      r.Origin.Headers,
+		SubtitleURL:  r.Origin.SubtitleURL,
+		SubtitleLang: r.Origin.SubtitleLang,
+	}
+}
+
+// Unfinished, kaydın henüz tamamlanmadığını bildirir.
+func (r Record) Unfinished() bool {
+	return r.Status != RecordCompleted
+}
+
+// Store, indirme kuyruğunu program yeniden başlatıldığında devam ettirilebilmesi için
+// bir JSON dosyasında saklar. Metotları eşzamanlı kullanıma uygundur; her değişiklik
+// dosyaya hemen yazılır.
+type Store struct {
+	mu       sync.Mutex
+	NextID   int       `json:"next_id"`
+	Records  []*Record `json:"records"`
+	filePath string
+}
+
+// OpenStore, dataDir içindeki kuyruk dosyasını okur. Dosya yoksa boş bir kuyruk döner.
+func OpenStore(dataDir string) (*Store, error) {
+	s := &Store{NextID: 1, filePath: filepath.Join(dataDir, StoreFile)}
+
+	data, err := os.ReadFile(s.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("indirme kuyruğu okunamadı: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("indirme kuyruğu bozuk (%s): %w", s.filePath, err)
+	}
+	return s, nil
+}
+
+// Add, yeni bir indirme kaydı ekler ve kaydedilen hâlini döner.
+// Aynı dosyaya ait tamamlanmamış bir kayıt varsa yenisi eklenmez, o kayıt güncellenir.
+func (s *Store) Add(name, url, path string, origin Origin) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, r := range s.Records {
+		if r.Path == path && r.Unfinished() {
+			r.Name, r.URL, r.Origin = name, url, origin
+			r.Status, r.Error, r.UpdatedAt = RecordPending, "", now
+			return *r, s.save()
+		}
+	}
+
+	r := &Record{
+		ID:        strconv.Itoa(s.NextID),
+		Name:      name,
+		URL:       url,
+		Path:      path,
+		Status:    RecordPending,
+		Origin:    origin,
+		AddedAt:   now,
+		UpdatedAt: now,
+	}
+	s.NextID++
+	s.Records = append(s.Records, r)
+	return *r, s.save()
+}
+
+// Get, verilen ID'ye sahip kaydı döner.
+func (s *Store) Get(id string) (Record, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r := s.find(id); r != nil {
+		return *r, true
+	}
+	return Record{}, false
+}
+
+// List, tüm kayıtları eklendikleri sırayla döner.
+func (s *Store) List() []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Record, 0, len(s.Records))
+	for _, r := range s.Records {
+		out = append(out, *r)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].AddedAt.Before(out[j].AddedAt) })
+	return out
+}
+
+// Finish, işin sonucunu kayda işler. Yenilenmiş bağlantı ve dosyanın son yolu da kayda yazılır.
+// İptal edilen işler devam ettirilmek üzere beklemede bırakılır.
+func (s *Store) Finish(job Job, err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.find(job.ID)
+	if r == nil {
+		return nil
+	}
+	r.URL, r.Path = job.URL, job.Path
+	r.UpdatedAt = time.Now()
+	switch {
+	case errors.Is(err, context.Canceled):
+		r.Status, r.Error = RecordPending, ""
+	case err != nil:
+		r.Status, r.Error = RecordFailed, err.Error()
+	default:
+		r.Status, r.Error = RecordCompleted, ""
+	}
+	return s.save()
+}
+
+// Remove, verilen ID'ye sahip kaydı siler. Kayıt tamamlanmamışsa yarım kalan .part dosyası da silinir.
+func (s *Store) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, r := range s.Records {
+		if r.ID != id {
+			continue
+		}
+		if r.Unfinished() {
+			if err := removePartial(r.Path); err != nil {
+				return fmt.Errorf("yarım dosya silinemedi: %w", err)
+			}
+		}
+		s.Records = append(s.Records[:i], s.Records[i+1:]...)
+		return s.save()
+	}
+	return fmt.Errorf("indirme bulunamadı: %s", id)
+}
+
+// Clear, tamamlanan kayıtları siler; all true ise tamamlanmamış kayıtları da yarım dosyalarıyla
+// birlikte siler. Yarım dosyası silinemeyen kayıtlar kuyrukta kalır ve hataları birlikte döner.
+// Silinen kayıt sayısını döner.
+func (s *Store) Clear(all bool) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := make([]*Record, 0, len(s.Records))
+	var (
+		errs    []error
+		removed int
+	)
+	for _, r := range s.Records {
+		if r.Unfinished() && !all {
+			kept = append(kept, r)
+			continue
+		}
+		if r.Unfinished() {
+			if err := removePartial(r.Path); err != nil {
+				errs = append(errs, fmt.Errorf("%s: yarım dosya silinemedi: %w", r.Name, err))
+				kept = append(kept, r)
+				continue
+			}
+		}
+		removed++
+	}
+	s.Records = kept
+	if err := s.save(); err != nil {
+		errs = append(errs, err)
+	}
+	return removed, errors.Join(errs...)
+}
+
+// removePartial, path için yarım kalan .part dosyasını ve HLS bölüt dizinini siler.
+func removePartial(path string) error {
+	if err := os.Remove(path + partSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return os.RemoveAll(path + segmentDirSuffix)
+}
+
+// find, ID'ye göre kaydı bulur. s.mu tutulurken çağrılmalıdır.
+func (s *Store) find(id string) *Record {
+	for _, r := range s.Records {
+		if r.ID == id {
+			return r
+		}
+	}
+	return nil
+}
+
+// save, kuyruğu dosyaya atomik olarak yazar. s.mu tutulurken çağrılmalıdır.
+func (s *Store) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := s.filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("indirme kuyruğu kaydedilemedi: %w", err)
+	}
+	return os.Rename(tmpPath, s.filePath)
+}
@@ -0,0 +1,72 @@
+package downloader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStoreClear(t *testing.T) {
+	tests := []struct {
+		name        string
+		all         bool
+		stuck       bool // bekleyen kaydın .part yolu silinemeyen bir dizin mi
+		wantRemoved int
+		wantKept    []string
+		wantErr     bool
+	}{
+		{"yalnızca tamamlananlar", false, false, 1, []string{"bekleyen"}, false},
+		{"tümü", true, false, 2, nil, false},
+		{"yarım dosya silinemezse kayıt kalır", true, true, 1, []string{"bekleyen"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			store, err := OpenStore(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			done, _ := store.Add("biten", "https://example.com/1", filepath.Join(dir, "1.mp4"), Origin{})
+			if err := store.Finish(done.Job(), nil); err != nil {
+				t.Fatal(err)
+			}
+			pendingPath := filepath.Join(dir, "2.mp4")
+			store.Add("bekleyen", "https://example.com/2", pendingPath, Origin{})
+			if tt.stuck {
+				// Boş olmayan bir dizin os.Remove ile silinemez
+				if err := os.MkdirAll(filepath.Join(pendingPath+partSuffix, "x"), 0755); err != nil {
+					t.Fatal(err)
+				}
+			} else if err := os.WriteFile(pendingPath+partSuffix, []byte("yarım"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			removed, err := store.Clear(tt.all)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Clear hatası = %v, beklenen hata: %v", err, tt.wantErr)
+			}
+			if removed != tt.wantRemoved {
+				t.Fatalf("%d kayıt silindi, beklenen %d", removed, tt.wantRemoved)
+			}
+
+			// Kuyruk dosyası da aynı kayıtları içermeli
+			reopened, err := OpenStore(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var kept []string
+			for _, r := range reopened.List() {
+				kept = append(kept, r.Name)
+			}
+			if len(kept) != len(tt.wantKept) || (len(kept) > 0 && kept[0] != tt.wantKept[0]) {
+				t.Fatalf("kalan kayıtlar %v, beklenen %v", kept, tt.wantKept)
+			}
+
+			_, statErr := os.Stat(pendingPath + partSuffix)
+			if partLeft := statErr == nil; partLeft != (!tt.all || tt.stuck) {
+				t.Fatalf(".part dosyası kaldı mı: %v", partLeft)
+			}
+		})
+	}
+}
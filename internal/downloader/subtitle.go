@@ -0,0 +1,176 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SubtitleOptions, altyazıların nasıl kaydedileceğini belirler.
+type SubtitleOptions struct {
+	Format string // "original" (kaynaktaki biçim) veya "srt" (VTT altyazılar SRT'ye çevrilir)
+	Mux    bool   // Altyazı ffmpeg ile videoyla birlikte tek bir MKV dosyasına gömülür
+}
+
+// AttachSubtitle, altyazıyı videoyla aynı adda bir yan dosya olarak kaydeder
+// (örn. "Bölüm 1.mp4" için "Bölüm 1.tr.srt"). opts.Mux ise altyazı ve video tek bir MKV'de
+// birleştirilip ayrı dosyalar silinir. headers altyazı isteğine eklenir. Videonun son yolunu döner.
+func AttachSubtitle(ctx context.Context, videoPath, subtitleURL, lang string, headers map[string]string, opts SubtitleOptions) (string, error) {
+	data, err := fetchBytes(ctx, &fetcher{headers: headers, onRetry: func(error, time.Duration, int) {}}, subtitleURL)
+	if err != nil {
+		return videoPath, fmt.Errorf("altyazı indirilemedi: %w", err)
+	}
+
+	format := subtitleFormat(subtitleURL, data)
+	if opts.Format == "srt" && format == "vtt" {
+		data, format = vttToSRT(data), "srt"
+	}
+
+	subPath := replaceExt(videoPath, "")
+	if lang != "" {
+		subPath += "." + lang
+	}
+	subPath += "." + format
+	if err := os.WriteFile(subPath, data, 0644); err != nil {
+		return videoPath, fmt.Errorf("altyazı kaydedilemedi: %w", err)
+	}
+
+	if !opts.Mux {
+		return videoPath, nil
+	}
+	return muxMKV(videoPath, subPath, lang)
+}
+
+// subtitleFormat, altyazının biçimini ("srt", "vtt", "ass" veya "ssa") adresin uzantısından,
+// bilinmiyorsa içeriğinden belirler.
+func subtitleFormat(subtitleURL string, data []byte) string {
+	if u, err := url.Parse(subtitleURL); err == nil {
+		switch ext := strings.ToLower(strings.TrimPrefix(path.Ext(u.Path), ".")); ext {
+		case "srt", "vtt", "ass", "ssa":
+			return ext
+		}
+	}
+
+	head := bytes.TrimPrefix(bytes.TrimSpace(data), []byte("\xef\xbb\xbf"))
+	switch {
+	case bytes.HasPrefix(head, []byte("WEBVTT")):
+		return "vtt"
+	case bytes.HasPrefix(head, []byte("[Script Info]")):
+		return "ass"
+	default:
+		return "srt"
+	}
+}
+
+var (
+	vttTiming = regexp.MustCompile(`^((?:\d+:)?\d{2}:\d{2}\.\d{3})\s+-->\s+((?:\d+:)?\d{2}:\d{2}\.\d{3})`)
+	vttTag    = regexp.MustCompile(`</?(?:c|v|lang|ruby|rt)(?:[.\s][^>]*)?>|<\d{2}:[\d:.]+>`)
+)
+
+// vttToSRT, WebVTT altyazıyı SubRip biçimine çevirir. Başlık, NOTE/STYLE/REGION blokları,
+// ipucu ayarları ve SRT'de karşılığı olmayan etiketler atılır; <i>, <b>, <u> korunur.
+func vttToSRT(data []byte) []byte {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.TrimPrefix(text, "\ufeff")
+
+	var (
+		out strings.Builder
+		n   int
+	)
+	for _, block := range strings.Split(text, "\n\n") {
+		lines := strings.Split(strings.Trim(block, "\n"), "\n")
+
+		// Zamanlama satırı olmayan bloklar (başlık, NOTE, STYLE, REGION) atlanır;
+		// zamanlama satırından önceki ipucu kimliği de atılır.
+		timing := -1
+		for i, line := range lines {
+			if vttTiming.MatchString(line) {
+				timing = i
+				break
+			}
+		}
+		if timing == -1 {
+			continue
+		}
+
+		m := vttTiming.FindStringSubmatch(lines[timing])
+		n++
+		fmt.Fprintf(&out, "%d\n%s --> %s\n", n, srtTimestamp(m[1]), srtTimestamp(m[2]))
+		for _, line := range lines[timing+1:] {
+			out.WriteString(vttTag.ReplaceAllString(line, ""))
+			out.WriteByte('\n')
+		}
+		out.WriteByte('\n')
+	}
+	return []byte(out.String())
+}
+
+// srtTimestamp, "01:02.345" veya "00:01:02.345" biçimindeki VTT zamanını "00:01:02,345" biçimine çevirir.
+func srtTimestamp(ts string) string {
+	if strings.Count(ts, ":") == 1 {
+		ts = "00:" + ts
+	}
+	hours, rest, _ := strings.Cut(ts, ":")
+	if h, err := strconv.Atoi(hours); err == nil {
+		hours = fmt.Sprintf("%02d", h)
+	}
+	return hours + ":" + strings.Replace(rest, ".", ",", 1)
+}
+
+// muxMKV, videoyu ve altyazıyı ffmpeg ile yeniden kodlamadan tek bir MKV dosyasında birleştirir.
+// Başarılı olursa ayrı dosyalar silinir ve MKV'nin yolu döner.
+func muxMKV(videoPath, subPath, lang string) (string, error) {
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return videoPath, fmt.Errorf("altyazıyı MKV'ye gömmek için ffmpeg gerekli: %w", err)
+	}
+
+	mkvPath := replaceExt(videoPath, ".mkv")
+	if mkvPath == videoPath {
+		mkvPath = replaceExt(videoPath, ".muxed.mkv")
+	}
+	partPath := mkvPath + partSuffix
+
+	args := []string{"-y", "-loglevel", "error", "-i", videoPath, "-i", subPath, "-map", "0", "-map", "1", "-c", "copy"}
+	if code := iso639_2(lang); code != "" {
+		args = append(args, "-metadata:s:s:0", "language="+code)
+	}
+	args = append(args, "-f", "matroska", partPath)
+
+	if out, err := exec.Command(ffmpeg, args...).CombinedOutput(); err != nil {
+		os.Remove(partPath)
+		return videoPath, fmt.Errorf("ffmpeg çalıştırılamadı: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	if err := os.Rename(partPath, mkvPath); err != nil {
+		return videoPath, fmt.Errorf("dosya taşınamadı: %w", err)
+	}
+
+	os.Remove(videoPath)
+	os.Remove(subPath)
+	_ = updateManifest(mkvPath, "", videoPath)
+	return mkvPath, nil
+}
+
+// iso639_2, iki harfli dil kodunu MKV'nin beklediği üç harfli koda çevirir.
+func iso639_2(lang string) string {
+	switch strings.ToLower(lang) {
+	case "":
+		return ""
+	case "tr":
+		return "tur"
+	case "en":
+		return "eng"
+	case "ja":
+		return "jpn"
+	default:
+		return lang
+	}
+}
@@ -0,0 +1,146 @@
+package downloader
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSrtTimestamp(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"01:02.345", "00:01:02,345"},
+		{"00:01:02.345", "00:01:02,345"},
+		{"1:01:02.345", "01:01:02,345"},
+		{"123:00:00.000", "123:00:00,000"},
+		{"00:00.000", "00:00:00,000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := srtTimestamp(tt.in); got != tt.want {
+				t.Fatalf("srtTimestamp(%q) = %q, beklenen %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVttToSRT(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "basit",
+			in:   "WEBVTT\n\n00:01.000 --> 00:02.500\nMerhaba\n\n00:03.000 --> 00:04.000\nİkinci\nsatır\n",
+			want: "1\n00:00:01,000 --> 00:00:02,500\nMerhaba\n\n2\n00:00:03,000 --> 00:00:04,000\nİkinci\nsatır\n\n",
+		},
+		{
+			name: "BOM, CRLF ve başlık metadatası",
+			in:   "\ufeffWEBVTT - Türkçe\r\nKind: captions\r\n\r\n01:00:00.000 --> 01:00:01.000\r\nSon\r\n",
+			want: "1\n01:00:00,000 --> 01:00:01,000\nSon\n\n",
+		},
+		{
+			name: "ipucu kimliği ve ayarları",
+			in:   "WEBVTT\n\nintro-1\n00:00:01.000 --> 00:00:02.000 align:start position:10%\nA\n",
+			want: "1\n00:00:01,000 --> 00:00:02,000\nA\n\n",
+		},
+		{
+			name: "NOTE, STYLE ve REGION atlanır",
+			in: "WEBVTT\n\nNOTE bu bir yorum\n\nSTYLE\n::cue { color: red }\n\n" +
+				"REGION\nid:alt\n\n00:01.000 --> 00:02.000\nB\n",
+			want: "1\n00:00:01,000 --> 00:00:02,000\nB\n\n",
+		},
+		{
+			name: "etiketler",
+			in: "WEBVTT\n\n00:01.000 --> 00:02.000\n<v Roger>Selam <c.yellow>sarı</c></v>\n" +
+				"<i>eğik</i> <b>kalın</b> <u>altı çizili</u> <00:01.500>karaoke <lang en>word</lang>\n",
+			want: "1\n00:00:01,000 --> 00:00:02,000\nSelam sarı\n<i>eğik</i> <b>kalın</b> <u>altı çizili</u> karaoke word\n\n",
+		},
+		{
+			name: "ipucu yok",
+			in:   "WEBVTT\n\nNOTE boş\n",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(vttToSRT([]byte(tt.in))); got != tt.want {
+				t.Fatalf("vttToSRT = %q, beklenen %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubtitleFormat(t *testing.T) {
+	tests := []struct {
+		url  string
+		data string
+		want string
+	}{
+		{"https://x/sub.VTT?token=1", "", "vtt"},
+		{"https://x/sub.ass", "", "ass"},
+		{"https://x/sub.ssa", "", "ssa"},
+		{"https://x/sub.srt", "WEBVTT", "srt"},
+		{"https://x/sub", "\xef\xbb\xbfWEBVTT\n", "vtt"},
+		{"https://x/sub.php?id=1", "  [Script Info]\n", "ass"},
+		{"https://x/sub", "1\n00:00:01,000 --> 00:00:02,000\n", "srt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := subtitleFormat(tt.url, []byte(tt.data)); got != tt.want {
+				t.Fatalf("subtitleFormat(%q) = %q, beklenen %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttachSubtitle(t *testing.T) {
+	const vtt = "WEBVTT\n\n00:01.000 --> 00:02.000\nMerhaba\n"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Referer") != "https://example.com/" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(vtt))
+	}))
+	defer srv.Close()
+	headers := map[string]string{"Referer": "https://example.com/"}
+
+	tests := []struct {
+		name     string
+		lang     string
+		format   string
+		wantPath string
+		want     string
+	}{
+		{"özgün biçim", "tr", "original", "Bölüm 1.tr.vtt", vtt},
+		{"SRT'ye çevrilir", "tr", "srt", "Bölüm 1.tr.srt", "1\n00:00:01,000 --> 00:00:02,000\nMerhaba\n\n"},
+		{"dil yok", "", "original", "Bölüm 1.vtt", vtt},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			video := filepath.Join(dir, "Bölüm 1.mp4")
+			got, err := AttachSubtitle(context.Background(), video, srv.URL+"/sub", tt.lang, headers, SubtitleOptions{Format: tt.format})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != video {
+				t.Fatalf("video yolu %q, beklenen %q", got, video)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, tt.wantPath))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Fatalf("altyazı içeriği %q, beklenen %q", data, tt.want)
+			}
+		})
+	}
+}
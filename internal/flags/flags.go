@@ -1,20 +1,38 @@
 package flags
 
 import (
+	"fmt"
 	"runtime"
 
 	"github.com/spf13/cobra"
+	"github.com/xeyossr/anitr-cli/internal/config"
 )
 
 type Flags struct {
-	DisableRPC   bool
-	PrintVersion bool
-	RofiMode     bool
-	RofiFlags    string
-	VLCPath      string
+	DisableRPC     bool
+	PrintVersion   bool
+	RofiMode       bool
+	RofiFlags      string
+	VLCPath        string
+	Player         string // Kullanılacak oynatıcı (vlc, mpv, iina, custom)
+	PlayerPath     string // Oynatıcının tam yolu
+	PlayerCmd      string // "custom" oynatıcının komut şablonu
+	WatchedPercent int    // Bölümün izlendi sayılması için gereken oynatma yüzdesi
+	Binge          bool   // Bölüm bitince sıradaki bölümü otomatik oynat
+	NoCache        bool   // Kaynak yanıtlarını önbellekten okuma ve önbelleğe yazma
 }
 
-func NewFlagsCmd() (*cobra.Command, *Flags) {
+// Validate, bayrak değerlerini yapılandırma dosyasıyla aynı kurallara göre kontrol eder.
+func (f *Flags) Validate() error {
+	if err := config.ValidateWatchedPercent(f.WatchedPercent); err != nil {
+		return fmt.Errorf("--watched-percent %w", err)
+	}
+	return nil
+}
+
+// NewFlagsCmd, kök komutu ve bayrakları oluşturur.
+// Bayrakların varsayılan değerleri yapılandırma dosyasından gelir; verilen bayraklar dosyayı geçersiz kılar.
+func NewFlagsCmd(cfg *config.Config) (*cobra.Command, *Flags) {
 	f := &Flags{}
 
 	cmd := &cobra.Command{
@@ -28,12 +46,28 @@ func NewFlagsCmd() (*cobra.Command, *Flags) {
 		},
 	}
 
-	cmd.PersistentFlags().BoolVar(&f.DisableRPC, "disable-rpc", false,
+	cmd.PersistentFlags().BoolVar(&f.DisableRPC, "disable-rpc", cfg.DisableRPC,
 		"Discord Rich Presence desteğini devre dışı bırakır.")
 
-	cmd.PersistentFlags().StringVar(&f.VLCPath, "vlc-path", "", "VLC oynatıcısının tam yolunu belirtir.")
+	cmd.PersistentFlags().StringVar(&f.VLCPath, "vlc-path", cfg.VLCPath, "VLC oynatıcısının tam yolunu belirtir.")
+
+	cmd.PersistentFlags().StringVar(&f.Player, "player", cfg.Player,
+		"Kullanılacak oynatıcı (vlc, mpv, iina, custom).")
+	cmd.PersistentFlags().StringVar(&f.PlayerPath, "player-path", cfg.PlayerPath,
+		"Oynatıcının tam yolunu belirtir (vlc için boşsa --vlc-path kullanılır).")
+	cmd.PersistentFlags().StringVar(&f.PlayerCmd, "player-cmd", cfg.PlayerCmd,
+		"custom oynatıcı için komut şablonu (örn: \"celluloid {url}\"); yer tutucular: {url}, {title}, {subtitle}, {start}, {referer}, {user_agent}.")
+
+	cmd.PersistentFlags().BoolVar(&f.Binge, "binge", cfg.Binge,
+		"Bölüm bitince geri sayımın ardından sezonun sıradaki bölümünü otomatik oynatır.")
+
+	cmd.PersistentFlags().IntVar(&f.WatchedPercent, "watched-percent", cfg.WatchedPercent,
+		"Bölümün izlendi sayılması için gereken oynatma yüzdesi (altında kalırsa kaldığı yerden devam edilir).")
+
+	cmd.PersistentFlags().BoolVar(&f.NoCache, "no-cache", false,
+		"Arama, sezon ve bölüm listelerini önbellek yerine her seferinde kaynaktan alır.")
 
-		cmd.SetVersionTemplate(`anitr-cli dev
+	cmd.SetVersionTemplate(`anitr-cli dev
 Lisans: GPL 3.0 (Özgür Yazılım)
 
 Go sürümü: unknown
@@ -61,7 +95,7 @@ Go sürümü: unknown
 			SilenceUsage:  true,
 			SilenceErrors: true,
 		}
-		rofiCmd.Flags().StringVarP(&f.RofiFlags, "rofi-flags", "f", "",
+		rofiCmd.Flags().StringVarP(&f.RofiFlags, "rofi-flags", "f", cfg.RofiFlags,
 			"Rofi'ye aktarılacak ek parametreler (örnek: --rofi-flags='-theme mytheme')")
 		cmd.AddCommand(rofiCmd)
 
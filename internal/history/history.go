@@ -2,27 +2,87 @@ package history
 
 import (
 	"encoding/json"
-	"io/ioutil"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
 )
 
-// WatchedEpisode represents the last watched episode for a given anime.
-type WatchedEpisode struct {
-	AnimeID       string `json:"anime_id"` // Using string for flexibility (ID or Slug)
-	LastEpisode int    `json:"last_episode"`
+// CurrentVersion is the schema version written by Save.
+const CurrentVersion = 2
+
+// UnknownSource marks migrated shows whose source cannot be determined. The very first
+// history format keyed entries by title when an anime had neither a slug nor an ID; such
+// entries are kept so no data is lost, but they cannot be resumed.
+const UnknownSource = "unknown"
+
+// slugPattern matches the slugs OpenAnime uses (lowercase words joined by hyphens).
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// EpisodeRecord is the watch record of a single episode.
+type EpisodeRecord struct {
+	Season         int       `json:"season,omitempty"`          // Season number, 0 if unknown (migrated entries)
+	Episode        int       `json:"episode,omitempty"`         // Episode number within the season
+	AbsoluteNumber int       `json:"absolute_number,omitempty"` // Episode order across all seasons
+	Title          string    `json:"title,omitempty"`           // Episode title
+	WatchedAt      time.Time `json:"watched_at"`                // When the episode was last played
+	Position       float64   `json:"position,omitempty"`        // Playback position in seconds
+	Duration       float64   `json:"duration,omitempty"`        // Episode length in seconds, 0 if unknown
+	Completed      bool      `json:"completed"`                 // Whether the episode counts as watched
 }
 
-// History stores a map of anime ID/slug to their last watched episode.
+// Show is the watch history of a single anime from a single source.
+type Show struct {
+	Source     string          `json:"source"`               // Source name (e.g. "animecix")
+	AnimeID    int             `json:"anime_id,omitempty"`   // Numeric anime ID, if the source uses one
+	Slug       string          `json:"slug,omitempty"`       // Anime slug, if the source uses one
+	Title      string          `json:"title"`                // Anime title
+	PosterURL  string          `json:"poster_url,omitempty"` // Poster image URL
+	IsMovie    bool            `json:"is_movie,omitempty"`   // Whether the anime is a movie
+	Fansub     string          `json:"fansub,omitempty"`     // Last selected fansub ID
+	Resolution string          `json:"resolution,omitempty"` // Last selected resolution label
+	UpdatedAt  time.Time       `json:"updated_at"`           // When the show was last watched
+	Episodes   []EpisodeRecord `json:"episodes"`             // Per-episode records, in watch order
+}
+
+// History stores the watch history of every show, keyed by Key(source, id, slug).
 type History struct {
-	Watched map[string]WatchedEpisode `json:"watched"`
+	Version  int              `json:"version"`
+	Shows    map[string]*Show `json:"shows"`
 	filePath string
 }
 
+// legacyEntry is the version 1 format: a flat map of anime ID/slug to last episode.
+type legacyEntry struct {
+	AnimeID     string    `json:"anime_id"`
+	LastEpisode int       `json:"last_episode"`
+	Source      string    `json:"source,omitempty"`
+	Title       string    `json:"title,omitempty"`
+	IsMovie     bool      `json:"is_movie,omitempty"`
+	Fansub      string    `json:"fansub,omitempty"`
+	Resolution  string    `json:"resolution,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+}
+
+// Key returns the identifier of a show. The source is part of the key so the
+// same numeric ID or slug from two sources never collides.
+func Key(source string, animeID int, slug string) string {
+	if slug != "" {
+		return source + "/" + slug
+	}
+	return source + "/" + strconv.Itoa(animeID)
+}
+
 // NewHistory creates a new History instance and loads data from the specified file.
+// Files written in the version 1 format are migrated in memory and rewritten on the next Save;
+// a copy of the original file is kept next to it with a ".v1.bak" suffix.
 func NewHistory(dataDir string) (*History, error) {
 	history := &History{
-		Watched:  make(map[string]WatchedEpisode),
+		Version:  CurrentVersion,
+		Shows:    make(map[string]*Show),
 		filePath: filepath.Join(dataDir, "watched_history.json"),
 	}
 	err := history.Load()
@@ -32,37 +92,227 @@ func NewHistory(dataDir string) (*History, error) {
 	return history, nil
 }
 
-// Load reads the watched history from the JSON file.
+// Load reads the watched history from the JSON file, migrating older formats.
 func (h *History) Load() error {
-	data, err := ioutil.ReadFile(h.filePath)
+	data, err := os.ReadFile(h.filePath)
 	if err != nil {
 		return err
 	}
-	return json.Unmarshal(data, &h.Watched)
+
+	var probe struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return fmt.Errorf("history file is not valid JSON: %w", err)
+	}
+
+	switch {
+	case probe.Version == 0:
+		var legacy map[string]legacyEntry
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return fmt.Errorf("failed to read legacy history: %w", err)
+		}
+		if err := os.WriteFile(h.filePath+".v1.bak", data, 0644); err != nil {
+			return fmt.Errorf("failed to back up legacy history: %w", err)
+		}
+		modTime := time.Now()
+		if info, err := os.Stat(h.filePath); err == nil {
+			modTime = info.ModTime()
+		}
+		h.migrateV1(legacy, modTime)
+		return nil
+	case probe.Version > CurrentVersion:
+		return fmt.Errorf("history file version %d is newer than supported version %d", probe.Version, CurrentVersion)
+	}
+
+	if err := json.Unmarshal(data, h); err != nil {
+		return err
+	}
+	if h.Shows == nil {
+		h.Shows = make(map[string]*Show)
+	}
+	h.Version = CurrentVersion
+	return nil
 }
 
-// Save writes the watched history to the JSON file.
+// migrateV1 converts version 1 entries. The season was never recorded, so migrated
+// episodes are marked completed with Season 0. AnimeciX numbered episodes across all
+// seasons, so its value becomes the absolute number; OpenAnime stored the number within
+// an unknown season, so its value is kept as Episode (see SeasonUnknown).
+// Entries without a source predate multi-source history; back then AnimeciX was
+// the only source keyed by numeric ID and OpenAnime the only one keyed by slug.
+// Keys that are neither (titles with spaces, capitals etc.) are kept under UnknownSource
+// regardless of the recorded source.
+// Entries without a timestamp use the modification time of the file.
+func (h *History) migrateV1(legacy map[string]legacyEntry, modTime time.Time) {
+	for key, entry := range legacy {
+		if entry.AnimeID == "" {
+			entry.AnimeID = key
+		}
+		if entry.UpdatedAt.IsZero() {
+			entry.UpdatedAt = modTime
+		}
+
+		show := &Show{
+			Source:     entry.Source,
+			Title:      entry.Title,
+			IsMovie:    entry.IsMovie,
+			Fansub:     entry.Fansub,
+			Resolution: entry.Resolution,
+			UpdatedAt:  entry.UpdatedAt,
+		}
+		if id, err := strconv.Atoi(entry.AnimeID); err == nil {
+			show.AnimeID = id
+			if show.Source == "" {
+				show.Source = "animecix"
+			}
+		} else if slugPattern.MatchString(entry.AnimeID) {
+			show.Slug = entry.AnimeID
+			if show.Source == "" {
+				show.Source = "openanime"
+			}
+		} else {
+			// The anime cannot be looked up again without its ID or slug
+			show.Source = UnknownSource
+		}
+		if show.Title == "" {
+			show.Title = entry.AnimeID
+		}
+		record := EpisodeRecord{WatchedAt: entry.UpdatedAt, Completed: true}
+		if show.Source == "openanime" {
+			record.Episode = entry.LastEpisode
+		} else {
+			record.AbsoluteNumber = entry.LastEpisode
+		}
+		show.Episodes = []EpisodeRecord{record}
+
+		showKey := Key(show.Source, show.AnimeID, show.Slug)
+		if show.Source == UnknownSource {
+			// The title takes the place of the slug so distinct titles never collide
+			showKey = Key(UnknownSource, 0, entry.AnimeID)
+		}
+		h.Shows[showKey] = show
+	}
+	h.Version = CurrentVersion
+}
+
+// Save writes the watched history to the JSON file. The file is written to a
+// temporary file first and renamed so a crash never leaves a truncated history.
 func (h *History) Save() error {
-	data, err := json.MarshalIndent(h.Watched, "", "  ")
+	h.Version = CurrentVersion
+	data, err := json.MarshalIndent(h, "", "  ")
 	if err != nil {
 		return err
 	}
-	return ioutil.WriteFile(h.filePath, data, 0644)
+
+	tmpPath := h.filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, h.filePath)
+}
+
+// Show returns the history of the show with the given key.
+func (h *History) Show(key string) (*Show, bool) {
+	show, ok := h.Shows[key]
+	return show, ok
 }
 
-// GetLastWatchedEpisode retrieves the last watched episode for a given anime.
-func (h *History) GetLastWatchedEpisode(animeID string) (int, bool) {
-	ep, ok := h.Watched[animeID]
+// RecordEpisode stores the watch record of an episode and updates the show's
+// metadata. Empty metadata fields in meta keep their previous values.
+func (h *History) RecordEpisode(meta Show, record EpisodeRecord) {
+	key := Key(meta.Source, meta.AnimeID, meta.Slug)
+	show, ok := h.Shows[key]
 	if !ok {
-		return 0, false
+		show = &Show{Source: meta.Source, AnimeID: meta.AnimeID, Slug: meta.Slug}
+		h.Shows[key] = show
+	}
+	if meta.Title != "" {
+		show.Title = meta.Title
+	}
+	if meta.PosterURL != "" {
+		show.PosterURL = meta.PosterURL
+	}
+	if meta.IsMovie {
+		show.IsMovie = true
+	}
+	if meta.Fansub != "" {
+		show.Fansub = meta.Fansub
 	}
-	return ep.LastEpisode, true
+	if meta.Resolution != "" {
+		show.Resolution = meta.Resolution
+	}
+
+	if record.WatchedAt.IsZero() {
+		record.WatchedAt = time.Now()
+	}
+	show.UpdatedAt = record.WatchedAt
+
+	// Aynı bölümün eski kaydı silinir, yeni kayıt sona eklenir
+	for i, ep := range show.Episodes {
+		if ep.sameEpisode(record) {
+			show.Episodes = append(show.Episodes[:i], show.Episodes[i+1:]...)
+			break
+		}
+	}
+	show.Episodes = append(show.Episodes, record)
 }
 
-// SetLastWatchedEpisode sets the last watched episode for a given anime.
-func (h *History) SetLastWatchedEpisode(animeID string, episodeNum int) {
-	h.Watched[animeID] = WatchedEpisode{
-		AnimeID:       animeID,
-		LastEpisode: episodeNum,
+// Recent returns the shows ordered from most to least recently watched.
+func (h *History) Recent() []*Show {
+	shows := make([]*Show, 0, len(h.Shows))
+	for _, show := range h.Shows {
+		shows = append(shows, show)
+	}
+	sort.Slice(shows, func(i, j int) bool {
+		if shows[i].UpdatedAt.Equal(shows[j].UpdatedAt) {
+			return shows[i].Title < shows[j].Title
+		}
+		return shows[i].UpdatedAt.After(shows[j].UpdatedAt)
+	})
+	return shows
+}
+
+// LastEpisode returns the most recently watched episode of the show.
+func (s *Show) LastEpisode() (EpisodeRecord, bool) {
+	if len(s.Episodes) == 0 {
+		return EpisodeRecord{}, false
+	}
+	last := s.Episodes[0]
+	for _, ep := range s.Episodes[1:] {
+		if !ep.WatchedAt.Before(last.WatchedAt) {
+			last = ep
+		}
+	}
+	return last, true
+}
+
+// Episode returns the record of the given episode. A season of 0 matches by
+// absolute number only, which is how migrated AnimeciX records are stored.
+func (s *Show) Episode(season, episode, absoluteNumber int) (EpisodeRecord, bool) {
+	target := EpisodeRecord{Season: season, Episode: episode, AbsoluteNumber: absoluteNumber}
+	for _, ep := range s.Episodes {
+		if ep.sameEpisode(target) {
+			return ep, true
+		}
+	}
+	return EpisodeRecord{}, false
+}
+
+// SeasonUnknown reports whether the record only carries an episode number within an
+// unknown season, as migrated OpenAnime records do. The same number may exist in
+// several seasons, so such records are resolved against the episode list on resume.
+func (e EpisodeRecord) SeasonUnknown() bool {
+	return e.Season == 0 && e.AbsoluteNumber == 0 && e.Episode > 0
+}
+
+// sameEpisode reports whether two records refer to the same episode.
+func (e EpisodeRecord) sameEpisode(other EpisodeRecord) bool {
+	if e.SeasonUnknown() || other.SeasonUnknown() {
+		return e.SeasonUnknown() && other.SeasonUnknown() && e.Episode == other.Episode
+	}
+	if e.Season > 0 && other.Season > 0 {
+		return e.Season == other.Season && e.Episode == other.Episode
 	}
+	return e.AbsoluteNumber == other.AbsoluteNumber
 }
@@ -0,0 +1,190 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMigrateV1(t *testing.T) {
+	modTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	watchedAt := time.Date(2024, 6, 1, 8, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		key     string
+		entry   legacyEntry
+		wantKey string
+		want    Show
+		wantEp  EpisodeRecord // WatchedAt and Completed are checked separately
+	}{
+		{
+			name:    "numeric ID without source",
+			key:     "1234",
+			entry:   legacyEntry{LastEpisode: 5},
+			wantKey: "animecix/1234",
+			want:    Show{Source: "animecix", AnimeID: 1234, Title: "1234", UpdatedAt: modTime},
+			wantEp:  EpisodeRecord{AbsoluteNumber: 5},
+		},
+		{
+			name:    "slug without source",
+			key:     "one-piece",
+			entry:   legacyEntry{LastEpisode: 1000, Title: "One Piece"},
+			wantKey: "openanime/one-piece",
+			want:    Show{Source: "openanime", Slug: "one-piece", Title: "One Piece", UpdatedAt: modTime},
+			wantEp:  EpisodeRecord{Episode: 1000},
+		},
+		{
+			name:    "recorded source and metadata",
+			key:     "42",
+			entry:   legacyEntry{AnimeID: "42", Source: "openanime", LastEpisode: 3, IsMovie: true, Fansub: "fs", Resolution: "1080p", UpdatedAt: watchedAt},
+			wantKey: "openanime/42",
+			want:    Show{Source: "openanime", AnimeID: 42, Title: "42", IsMovie: true, Fansub: "fs", Resolution: "1080p", UpdatedAt: watchedAt},
+			wantEp:  EpisodeRecord{Episode: 3},
+		},
+		{
+			name:    "recorded animecix source",
+			key:     "77",
+			entry:   legacyEntry{Source: "animecix", LastEpisode: 30},
+			wantKey: "animecix/77",
+			want:    Show{Source: "animecix", AnimeID: 77, Title: "77", UpdatedAt: modTime},
+			wantEp:  EpisodeRecord{AbsoluteNumber: 30},
+		},
+		{
+			name:    "title with spaces",
+			key:     "Shingeki no Kyojin",
+			entry:   legacyEntry{LastEpisode: 7},
+			wantKey: "unknown/Shingeki no Kyojin",
+			want:    Show{Source: UnknownSource, Title: "Shingeki no Kyojin", UpdatedAt: modTime},
+			wantEp:  EpisodeRecord{AbsoluteNumber: 7},
+		},
+		{
+			name:    "title without spaces",
+			key:     "Naruto",
+			entry:   legacyEntry{LastEpisode: 2},
+			wantKey: "unknown/Naruto",
+			want:    Show{Source: UnknownSource, Title: "Naruto", UpdatedAt: modTime},
+			wantEp:  EpisodeRecord{AbsoluteNumber: 2},
+		},
+		{
+			name:    "title with recorded source",
+			key:     "Kimi no Na wa.",
+			entry:   legacyEntry{Source: "animecix", LastEpisode: 1},
+			wantKey: "unknown/Kimi no Na wa.",
+			want:    Show{Source: UnknownSource, Title: "Kimi no Na wa.", UpdatedAt: modTime},
+			wantEp:  EpisodeRecord{AbsoluteNumber: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &History{Shows: make(map[string]*Show)}
+			h.migrateV1(map[string]legacyEntry{tt.key: tt.entry}, modTime)
+
+			show, ok := h.Shows[tt.wantKey]
+			if !ok {
+				t.Fatalf("key %q not found: %v", tt.wantKey, h.Shows)
+			}
+			got := *show
+			got.Episodes = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("migrateV1 = %+v, want %+v", got, tt.want)
+			}
+			if len(show.Episodes) != 1 || !show.Episodes[0].Completed || show.Episodes[0].WatchedAt != got.UpdatedAt {
+				t.Fatalf("unexpected episode records: %+v", show.Episodes)
+			}
+			ep := show.Episodes[0]
+			ep.WatchedAt, ep.Completed = time.Time{}, false
+			if ep != tt.wantEp {
+				t.Fatalf("migrated episode = %+v, want %+v", ep, tt.wantEp)
+			}
+			if h.Version != CurrentVersion {
+				t.Fatalf("version = %d, want %d", h.Version, CurrentVersion)
+			}
+		})
+	}
+}
+
+func TestMigratedOpenAnimeEpisode(t *testing.T) {
+	// A multi-season OpenAnime show whose v1 entry stored episode 3 of an unknown season
+	h := &History{Shows: make(map[string]*Show)}
+	h.migrateV1(map[string]legacyEntry{"attack-on-titan": {LastEpisode: 3}}, time.Now())
+	show := h.Shows["openanime/attack-on-titan"]
+
+	tests := []struct {
+		name                      string
+		season, episode, absolute int
+		wantOK                    bool
+	}{
+		{"same absolute number in season 1", 1, 3, 3, false},
+		{"same number in season 2", 2, 3, 28, false},
+		{"absolute number only", 0, 0, 3, false},
+		{"unknown season", 0, 3, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := show.Episode(tt.season, tt.episode, tt.absolute); ok != tt.wantOK {
+				t.Fatalf("Episode(%d, %d, %d) found = %v, want %v", tt.season, tt.episode, tt.absolute, ok, tt.wantOK)
+			}
+		})
+	}
+
+	if last, _ := show.LastEpisode(); !last.SeasonUnknown() || last.Episode != 3 {
+		t.Fatalf("last episode = %+v, want episode 3 of an unknown season", last)
+	}
+
+	// Watching S2E3 adds a new record instead of replacing the migrated one
+	h.RecordEpisode(Show{Source: "openanime", Slug: "attack-on-titan"}, EpisodeRecord{Season: 2, Episode: 3, AbsoluteNumber: 28})
+	if len(show.Episodes) != 2 {
+		t.Fatalf("unexpected episode records: %+v", show.Episodes)
+	}
+	if last, _ := show.LastEpisode(); last.Season != 2 || last.Episode != 3 {
+		t.Fatalf("last episode = %+v, want S2E3", last)
+	}
+}
+
+func TestLoadV1File(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "watched_history.json")
+	legacy := `{
+  "123": {"anime_id": "123", "last_episode": 4},
+  "Bleach": {"anime_id": "Bleach", "last_episode": 9}
+}`
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := NewHistory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Shows) != 2 || h.Shows["animecix/123"] == nil || h.Shows["unknown/Bleach"] == nil {
+		t.Fatalf("unexpected migrated shows: %v", h.Shows)
+	}
+	if backup, err := os.ReadFile(path + ".v1.bak"); err != nil || string(backup) != legacy {
+		t.Fatalf("unexpected backup file: %v", err)
+	}
+
+	// After saving, the file is version 2 and reloads to the same shows
+	if err := h.Save(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := NewHistory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded.Shows) != 2 || reloaded.Shows["unknown/Bleach"].Source != UnknownSource {
+		t.Fatalf("unexpected reloaded shows: %v", reloaded.Shows)
+	}
+}
+
+func TestLoadNewerVersion(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "watched_history.json"), []byte(`{"version": 99, "shows": {}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewHistory(dir); err == nil {
+		t.Fatal("expected an error for a newer file version")
+	}
+}
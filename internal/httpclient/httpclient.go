@@ -0,0 +1,347 @@
+// httpclient paketi, kaynakların ve indiricinin paylaştığı HTTP istemcisini sağlar.
+//
+// Tüm istemciler aynı bağlantı havuzunu ve vekil sunucu ayarını kullanır. Her istemcinin
+// kendi süre sınırı, yeniden deneme sayısı, hız sınırı ve varsayılan başlıkları vardır;
+// böylece her kaynak kendi sunucusuna uygun ayarlarla istek yapar ve takılan bir istek
+// programı kilitlemez.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultTimeout, süre sınırı verilmemiş istemcilerin istek başına süre sınırıdır.
+	DefaultTimeout = 30 * time.Second
+
+	initialBackoff = 500 * time.Millisecond
+	maxBackoff     = 10 * time.Second
+)
+
+// ErrStalled, yanıt gövdesinden IdleTimeout boyunca veri gelmediğinde döner.
+var ErrStalled = errors.New("sunucudan veri gelmiyor")
+
+// StatusError, sunucu 2xx dışında bir durum koduyla yanıt verdiğinde döner.
+type StatusError struct {
+	URL    string // İstenen adres
+	Code   int    // Durum kodu
+	Status string // Durum satırı (örn. "404 Not Found")
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s beklenmeyen yanıt döndü: %s", e.URL, e.Status)
+}
+
+// CheckStatus, yanıtın durum kodu 2xx değilse *StatusError döner.
+func CheckStatus(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	return &StatusError{URL: resp.Request.URL.String(), Code: resp.StatusCode, Status: resp.Status}
+}
+
+// IsUnavailable, hatanın sunucuya ulaşılamadığını (bağlantı, süre aşımı, kesilen yanıt)
+// veya sunucunun 5xx ile yanıt verdiğini gösterip göstermediğini döner. 4xx yanıtları ve
+// ayrıştırma hataları sunucunun ayakta olduğunu gösterdiğinden false döner.
+func IsUnavailable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code >= 500
+	}
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr) ||
+		errors.Is(err, ErrStalled) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// Settings, tüm istemcilerin paylaştığı ayarlardır.
+type Settings struct {
+	Proxy   string        // Vekil sunucu adresi (http://, https:// veya socks5://); boşsa ortam değişkenleri kullanılır
+	Timeout time.Duration // Süre sınırı verilmemiş istemcilerin istek başına süre sınırı
+}
+
+var (
+	proxyURL       atomic.Pointer[url.URL]
+	defaultTimeout atomic.Int64
+)
+
+func init() {
+	defaultTimeout.Store(int64(DefaultTimeout))
+}
+
+// Configure, paylaşılan ayarları uygular. Başlangıçta yapılandırma okunduktan sonra çağrılır.
+func Configure(s Settings) error {
+	if s.Proxy == "" {
+		proxyURL.Store(nil)
+	} else {
+		u, err := ParseProxy(s.Proxy)
+		if err != nil {
+			return err
+		}
+		proxyURL.Store(u)
+	}
+	if s.Timeout > 0 {
+		defaultTimeout.Store(int64(s.Timeout))
+	}
+	return nil
+}
+
+// ParseProxy, vekil sunucu adresini ayrıştırır ve desteklenen bir şema kullandığını kontrol eder.
+func ParseProxy(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("vekil sunucu adresi geçersiz: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("vekil sunucu adresi http://, https:// veya socks5:// ile başlamalı: %q", raw)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("vekil sunucu adresinde sunucu yok: %q", raw)
+	}
+	return u, nil
+}
+
+// transport, tüm istemcilerin paylaştığı bağlantı havuzudur.
+var transport = &http.Transport{
+	Proxy: func(req *http.Request) (*url.URL, error) {
+		if u := proxyURL.Load(); u != nil {
+			return u, nil
+		}
+		return http.ProxyFromEnvironment(req)
+	},
+	DialContext: (&net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext,
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          100,
+	MaxIdleConnsPerHost:   8,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ResponseHeaderTimeout: 30 * time.Second,
+	ExpectContinueTimeout: time.Second,
+}
+
+// Options, bir istemcinin ayarlarıdır.
+type Options struct {
+	Headers     map[string]string // Her isteğe eklenen başlıklar (istekte zaten varsa eklenmez)
+	Timeout     time.Duration     // İstek başına süre sınırı (gövdenin okunması dahil); 0 ise varsayılan, <0 ise sınırsız
+	IdleTimeout time.Duration     // Gövde okunurken veri gelmeden beklenebilecek en uzun süre; 0 ise sınırsız
+	Retries     int               // Ağ hataları ve 5xx/429 yanıtlarında yeniden deneme sayısı
+	RateLimit   float64           // Saniyedeki en fazla istek sayısı; 0 ise sınırsız
+}
+
+// Client, ayarlarına göre istek yapan bir HTTP istemcisidir. Aynı anda birden fazla
+// goroutine tarafından kullanılabilir.
+type Client struct {
+	opts    Options
+	http    *http.Client
+	limiter *limiter
+}
+
+// New, verilen ayarlarla bir istemci oluşturur.
+func New(opts Options) *Client {
+	c := &Client{opts: opts, http: &http.Client{Transport: transport}}
+	if opts.RateLimit > 0 {
+		c.limiter = &limiter{interval: time.Duration(float64(time.Second) / opts.RateLimit)}
+	}
+	return c
+}
+
+// Default, kaynağa özgü olmayan istekler (görseller vb.) için kullanılan istemcidir.
+var Default = New(Options{Retries: 2})
+
+// Get, adrese GET isteği yapar.
+func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP isteği oluşturulamadı: %w", err)
+	}
+	return c.Do(req)
+}
+
+// Head, adrese HEAD isteği yapar.
+func (c *Client) Head(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP isteği oluşturulamadı: %w", err)
+	}
+	return c.Do(req)
+}
+
+// Do, isteği istemcinin başlıkları, süre sınırı ve hız sınırıyla gönderir. Gövdesiz istekler
+// ağ hatalarında ve 5xx/429 yanıtlarında üstel bekleme ile yeniden denenir (Retry-After
+// başlığına uyulur). Son denemenin yanıtı durum kodundan bağımsız olarak döner; gövde
+// kapatıldığında isteğin süre sınırı da serbest bırakılır.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	retries := c.opts.Retries
+	if req.Body != nil && req.Body != http.NoBody {
+		retries = 0
+	}
+
+	backoff := initialBackoff
+	for attempt := 0; ; attempt++ {
+		resp, err := c.do(req)
+		if attempt >= retries || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		wait := backoff
+		if err == nil {
+			if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+				return resp, nil
+			}
+			if after, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+				wait = min(after, maxBackoff)
+			}
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// do, isteği tek bir kez gönderir.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if c.limiter != nil {
+		if err := c.limiter.wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	timeout := c.opts.Timeout
+	if timeout == 0 {
+		timeout = time.Duration(defaultTimeout.Load())
+	}
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(req.Context(), timeout)
+	} else {
+		ctx, cancel = context.WithCancel(req.Context())
+	}
+
+	req = req.Clone(ctx)
+	for k, v := range c.opts.Headers {
+		if req.Header.Get(k) == "" {
+			req.Header.Set(k, v)
+		}
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = newBody(resp.Body, cancel, c.opts.IdleTimeout)
+	return resp, nil
+}
+
+// body, kapatıldığında isteğin bağlamını serbest bırakan ve IdleTimeout boyunca veri
+// gelmezse isteği iptal eden yanıt gövdesidir.
+type body struct {
+	io.ReadCloser
+	cancel  context.CancelFunc
+	idle    time.Duration
+	timer   *time.Timer
+	stalled atomic.Bool
+}
+
+func newBody(rc io.ReadCloser, cancel context.CancelFunc, idle time.Duration) *body {
+	b := &body{ReadCloser: rc, cancel: cancel, idle: idle}
+	if idle > 0 {
+		b.timer = time.AfterFunc(idle, func() {
+			b.stalled.Store(true)
+			cancel()
+		})
+	}
+	return b
+}
+
+func (b *body) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if b.timer != nil && n > 0 {
+		b.timer.Reset(b.idle)
+	}
+	if err != nil && err != io.EOF && b.stalled.Load() {
+		err = fmt.Errorf("%s boyunca %w", b.idle, ErrStalled)
+	}
+	return n, err
+}
+
+func (b *body) Close() error {
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// limiter, istekler arasında en az interval kadar süre bırakır.
+type limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// wait, sıradaki isteğin zamanı gelene kadar bekler.
+func (l *limiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	return sleep(ctx, time.Until(at))
+}
+
+// sleep, d kadar veya bağlam iptal edilene kadar bekler.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// retryAfter, saniye veya HTTP tarihi olarak verilen Retry-After başlığını ayrıştırır.
+func retryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(header); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
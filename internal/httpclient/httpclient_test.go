@@ -0,0 +1,68 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckStatus(t *testing.T) {
+	tests := []struct {
+		code int
+		want bool // hata beklenir mi
+	}{
+		{200, false},
+		{204, false},
+		{301, true},
+		{404, true},
+		{422, true},
+		{503, true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.code), func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "https://example.com/x", nil)
+			resp := &http.Response{StatusCode: tt.code, Status: http.StatusText(tt.code), Request: req}
+			err := CheckStatus(resp)
+			if (err != nil) != tt.want {
+				t.Fatalf("CheckStatus(%d) = %v", tt.code, err)
+			}
+			var statusErr *StatusError
+			if err != nil && (!errors.As(err, &statusErr) || statusErr.Code != tt.code) {
+				t.Fatalf("CheckStatus(%d) *StatusError dönmedi: %v", tt.code, err)
+			}
+		})
+	}
+}
+
+func TestIsUnavailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	addr := srv.URL
+	srv.Close()
+	_, transportErr := New(Options{}).Get(context.Background(), addr)
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"bağlantı hatası", transportErr, true},
+		{"sarılmış bağlantı hatası", fmt.Errorf("istek: %w", transportErr), true},
+		{"5xx", &StatusError{Code: 502}, true},
+		{"4xx", &StatusError{Code: 404}, false},
+		{"422", fmt.Errorf("x: %w", &StatusError{Code: 422}), false},
+		{"takılan yanıt", fmt.Errorf("60s boyunca %w", ErrStalled), true},
+		{"kesilen yanıt", io.ErrUnexpectedEOF, true},
+		{"ayrıştırma hatası", errors.New("JSON ayrıştırma başarısız"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsUnavailable(tt.err); got != tt.want {
+				t.Fatalf("IsUnavailable(%v) = %v, beklenen %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
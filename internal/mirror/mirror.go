@@ -0,0 +1,212 @@
+// mirror paketi, aynı hizmetin birden fazla adresi (aynası) arasında otomatik geçiş yapar.
+//
+// İstekler son çalışan aynaya gönderilir. Ayna ulaşılamaz olursa (bağlantı hatası veya
+// 5xx yanıtı) diğer aynalar sırayla yoklanır, ayakta olan ilk aynada istek tekrarlanır ve başarılı olan ayna sonraki
+// çalıştırmalarda da önce denenmek üzere durum dizinine kaydedilir.
+package mirror
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/xeyossr/anitr-cli/internal/httpclient"
+	"github.com/xeyossr/anitr-cli/internal/paths"
+)
+
+// StateFile, son çalışan aynaların kaydedildiği dosyanın adıdır.
+const StateFile = "mirrors.json"
+
+var (
+	// stateMu, aynı dosyayı paylaşan ayna listelerinin kaydı birlikte yazmasını engeller.
+	stateMu sync.Mutex
+
+	// checkClient, aynaların ayakta olup olmadığını yoklamak için kullanılır.
+	checkClient = httpclient.New(httpclient.Options{Timeout: 5 * time.Second})
+)
+
+// Set, bir hizmetin sırasıyla denenen aynalarıdır. Aynı anda birden fazla goroutine
+// tarafından kullanılabilir.
+type Set struct {
+	name string
+	urls []string
+
+	mu      sync.Mutex
+	current int
+	loaded  bool
+}
+
+// New, name adıyla kaydedilecek bir ayna listesi oluşturur. Boş adresler atlanır;
+// ilk adres, kayıtlı bir ayna yoksa önce denenir.
+func New(name string, urls ...string) *Set {
+	s := &Set{name: name}
+	for _, u := range urls {
+		if u != "" {
+			s.urls = append(s.urls, u)
+		}
+	}
+	return s
+}
+
+// URLs, aynaların tümünü tanımlandıkları sırayla döner.
+func (s *Set) URLs() []string {
+	return append([]string(nil), s.urls...)
+}
+
+// Current, isteklerin gönderileceği aynayı (son çalışan ayna) döner.
+func (s *Set) Current() string {
+	if len(s.urls) == 0 {
+		return ""
+	}
+	return s.urls[s.index()]
+}
+
+// Do, fn'i geçerli aynayla çalıştırır. fn'in hatası aynanın ulaşılamadığını gösteriyorsa
+// (bkz. httpclient.IsUnavailable) diğer aynalar sırayla yoklanır ve ayakta olanlarla fn
+// tekrarlanır; başarılı olan ayna hatırlanır. Ayrıştırma hataları ve 4xx yanıtları isteğe
+// özgü olduğundan geçiş yapılmadan döner. Hiçbir ayna çalışmazsa geçerli aynadaki ilk hata döner.
+func (s *Set) Do(ctx context.Context, fn func(base string) error) error {
+	if len(s.urls) == 0 {
+		return fmt.Errorf("%s için tanımlı adres yok", s.name)
+	}
+
+	start := s.index()
+	err := fn(s.urls[start])
+	if err == nil || ctx.Err() != nil || !httpclient.IsUnavailable(err) {
+		return err
+	}
+
+	for k := 1; k < len(s.urls); k++ {
+		i := (start + k) % len(s.urls)
+		if Check(ctx, s.urls[i]) != nil {
+			continue
+		}
+		nextErr := fn(s.urls[i])
+		if nextErr == nil {
+			s.use(i)
+			return nil
+		}
+		// Ayna yanıt verdi ama istek geçersiz; diğer aynalar da aynı yanıtı verecektir
+		if ctx.Err() != nil || !httpclient.IsUnavailable(nextErr) {
+			return nextErr
+		}
+	}
+	return err
+}
+
+// Healthy, geçerli ayna ayaktaysa onu, değilse ayakta olan ilk aynayı döner ve hatırlar.
+// Hiçbiri yanıt vermezse geçerli ayna döner. İstek yapılmadan yalnızca adresi kullanılan
+// (örn. oynatıcıya verilen) aynalar için kullanılır.
+func (s *Set) Healthy(ctx context.Context) string {
+	if len(s.urls) <= 1 {
+		return s.Current()
+	}
+
+	start := s.index()
+	for k := 0; k < len(s.urls); k++ {
+		i := (start + k) % len(s.urls)
+		if Check(ctx, s.urls[i]) == nil {
+			if i != start {
+				s.use(i)
+			}
+			return s.urls[i]
+		}
+	}
+	return s.urls[start]
+}
+
+// Check, aynanın ayakta olup olmadığını yoklar. Sunucu bağlantıyı kabul eder ve 5xx
+// dışında bir yanıt verirse ayna ayakta sayılır.
+func Check(ctx context.Context, base string) error {
+	resp, err := checkClient.Head(ctx, base)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("%s yanıt vermiyor: %s", base, resp.Status)
+	}
+	return nil
+}
+
+// index, geçerli aynanın sırasını döner; ilk çağrıda kayıtlı ayna yüklenir.
+func (s *Set) index() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loaded {
+		s.loaded = true
+		if saved, ok := loadState()[s.name]; ok {
+			for i, u := range s.urls {
+				if u == saved {
+					s.current = i
+					break
+				}
+			}
+		}
+	}
+	return s.current
+}
+
+// use, i. aynayı geçerli ayna yapar ve kaydeder. Kayıt yazılamazsa ayna yalnızca bu
+// çalıştırma boyunca hatırlanır.
+func (s *Set) use(i int) {
+	s.mu.Lock()
+	s.current = i
+	s.mu.Unlock()
+
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	state := loadState()
+	state[s.name] = s.urls[i]
+	_ = saveState(state)
+}
+
+// statePath, kayıt dosyasının yolunu döner.
+func statePath() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, StateFile), nil
+}
+
+// loadState, hizmet adına göre son çalışan aynaları okur. Dosya yoksa veya okunamazsa boş döner.
+func loadState() map[string]string {
+	state := make(map[string]string)
+	path, err := statePath()
+	if err != nil {
+		return state
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return make(map[string]string)
+	}
+	return state
+}
+
+// saveState, son çalışan aynaları atomik olarak yazar.
+func saveState(state map[string]string) error {
+	path, err := statePath()
+	if err != nil {
+		return err
+	}
+	if _, err := paths.Ensure(filepath.Dir(path), nil); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
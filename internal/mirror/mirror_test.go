@@ -0,0 +1,52 @@
+package mirror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/xeyossr/anitr-cli/internal/httpclient"
+)
+
+func TestDo(t *testing.T) {
+	tests := []struct {
+		name      string
+		firstErr  error // ilk aynada fn'in döndüğü hata
+		wantErr   bool
+		wantCalls int // fn çağrı sayısı
+	}{
+		{"başarılı", nil, false, 1},
+		{"5xx yanıtında geçiş", &httpclient.StatusError{Code: 503, Status: "503 Service Unavailable"}, false, 2},
+		{"422 yanıtında geçiş yok", &httpclient.StatusError{Code: 422, Status: "422 Unprocessable Entity"}, true, 1},
+		{"ayrıştırma hatasında geçiş yok", &json.SyntaxError{}, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_STATE_HOME", t.TempDir())
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			defer srv.Close()
+
+			set := New("test", "https://first.invalid/", srv.URL)
+			calls := 0
+			err := set.Do(context.Background(), func(base string) error {
+				calls++
+				if base == "https://first.invalid/" {
+					return tt.firstErr
+				}
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Do hatası = %v", err)
+			}
+			if err != nil && !errors.Is(err, tt.firstErr) {
+				t.Fatalf("Do ilk hatayı dönmedi: %v", err)
+			}
+			if calls != tt.wantCalls {
+				t.Fatalf("fn %d kez çağrıldı, beklenen %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
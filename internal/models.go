@@ -1,22 +1,43 @@
 package internal
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/xeyossr/anitr-cli/internal/httpclient"
+	"github.com/xeyossr/anitr-cli/internal/mirror"
 	"github.com/xeyossr/anitr-cli/internal/utils"
 )
 
 // Config, uygulamanın temel yapılandırma ayarlarını temsil eder.
 type Config struct {
 	BaseUrl        string            // API'nin temel adresi
-	AlternativeUrl string            // Alternatif API adresi (fallback)
-	VideoPlayers   []string          // Kullanılabilir video oynatıcılar
+	AlternativeUrl string            // Alternatif API adresi (BaseUrl çalışmazsa denenir)
+	VideoPlayers   []string          // Video oynatıcıların adresleri (sırayla denenir)
 	HttpHeaders    map[string]string // HTTP isteklerinde kullanılacak başlıklar
 }
 
+// NewClient, kaynağın başlıklarını (User-Agent, Referer vb.) her isteğe ekleyen bir HTTP istemcisi oluşturur.
+func (c Config) NewClient(opts httpclient.Options) *httpclient.Client {
+	opts.Headers = c.HttpHeaders
+	return httpclient.New(opts)
+}
+
+// Mirrors, API adreslerini (önce BaseUrl, sonra AlternativeUrl) sırayla denenecek aynalar olarak döner.
+func (c Config) Mirrors(name string) *mirror.Set {
+	return mirror.New(name, c.BaseUrl, c.AlternativeUrl)
+}
+
+// PlayerMirrors, video oynatıcı adreslerini sırayla denenecek aynalar olarak döner.
+func (c Config) PlayerMirrors(name string) *mirror.Set {
+	return mirror.New(name, c.VideoPlayers...)
+}
+
 // UiParams, UI (kullanıcı arayüzü) ile ilgili parametreleri temsil eder.
 type UiParams struct {
 	Mode      string        // Arayüz modu: "rofi" veya "tui"
@@ -30,17 +51,18 @@ type UiParams struct {
 
 // RPCParams, Discord Rich Presence için gönderilecek bilgileri içerir.
 type RPCParams struct {
-	AnimeTitle    string // Anime başlığı
-	EpisodeTitle  string // Bölüm başlığı
+	AnimeTitle     string // Anime başlığı
+	EpisodeTitle   string // Bölüm başlığı
 	CurrentEpisode int    // Mevcut bölüm numarası
 	TotalEpisodes  int    // Toplam bölüm sayısı
-	Details       string // Aktivite detayı
-	State         string // Kullanıcı durumu
-	LargeImage    string // Büyük görselin adı
-	LargeText     string // Büyük görsel üzerine gelindiğinde gösterilecek yazı
-	SmallImage    string // Küçük görselin adı
-	SmallText     string // Küçük görsel üzerine gelindiğinde gösterilecek yazı
+	Details        string // Aktivite detayı
+	State          string // Kullanıcı durumu
+	LargeImage     string // Büyük görselin adı
+	LargeText      string // Büyük görsel üzerine gelindiğinde gösterilecek yazı
+	SmallImage     string // Küçük görselin adı
+	SmallText      string // Küçük görsel üzerine gelindiğinde gösterilecek yazı
 	MyAnimeListURL string // MyAnimeList URL'si
+	Paused         bool   // Oynatıcı duraklatıldı mı
 }
 
 // GetStringPtr, map içinden verilen anahtara karşılık gelen değeri *string olarak döner.
@@ -61,27 +83,60 @@ func GetString(m map[string]interface{}, key string) string {
 	return ""
 }
 
-// GetJson, verilen URL'ye HTTP GET isteği gönderir, gelen JSON yanıtı çözümler.
-// Başarılı olursa çözülmüş veriyi interface{} olarak döner, aksi hâlde hata döner.
-func GetJson(url string, headers map[string]string) (interface{}, error) {
-	client := &http.Client{}
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("HTTP isteği oluşturulamadı: %w", err)
+// GetInt, map içinden verilen anahtara karşılık gelen değeri int olarak döner.
+// JSON sayıları (float64), int ve sayı içeren string değerler desteklenir.
+// Değer bulunamazsa veya sayıya çevrilemezse false döner.
+func GetInt(m map[string]interface{}, key string) (int, bool) {
+	switch v := m[key].(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	case string:
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return 0, false
+		}
+		return n, true
 	}
+	return 0, false
+}
 
-	// İstek başlıklarını ayarla
-	for k, m := range headers {
-		req.Header.Set(k, m)
+// GetTime, map içinden verilen anahtarlardan ilk ayrıştırılabilen tarihi döner.
+// RFC 3339 ve "2006-01-02 15:04:05", "2006-01-02" biçimleri desteklenir.
+// Hiçbiri bulunamazsa sıfır değer döner.
+func GetTime(m map[string]interface{}, keys ...string) time.Time {
+	layouts := []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"}
+	for _, key := range keys {
+		val, ok := m[key].(string)
+		if !ok || val == "" {
+			continue
+		}
+		for _, layout := range layouts {
+			if t, err := time.Parse(layout, val); err == nil {
+				return t
+			}
+		}
 	}
+	return time.Time{}
+}
 
-	resp, err := client.Do(req)
+// GetJson, verilen URL'ye istemciyle HTTP GET isteği gönderir, gelen JSON yanıtı çözümler.
+// Başlıklar, süre sınırı ve yeniden denemeler istemcinin ayarlarından gelir.
+// Başarılı olursa çözülmüş veriyi interface{} olarak döner; 2xx dışındaki yanıtlarda
+// *httpclient.StatusError, diğer durumlarda hata döner.
+func GetJson(ctx context.Context, client *httpclient.Client, url string) (interface{}, error) {
+	resp, err := client.Get(ctx, url)
 	if err != nil {
 		return nil, fmt.Errorf("HTTP isteği başarısız: %w", err)
 	}
 	defer resp.Body.Close()
 
+	// Hata sayfaları JSON olsa bile başarılı yanıt sayılmaz
+	if err := httpclient.CheckStatus(resp); err != nil {
+		return nil, err
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("HTTP yanıtı okunamadı: %w", err)
@@ -94,4 +149,4 @@ func GetJson(url string, headers map[string]string) (interface{}, error) {
 	}
 
 	return result, nil
-}
\ No newline at end of file
+}
@@ -1,20 +1,37 @@
 // models paketi, anime verilerini ve ilgili yapılarını tanımlar.
 package models
 
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // AnimeSource arayüzü, farklı anime kaynaklarından veri çekme işlevlerini tanımlar.
+// Bağlam iptal edildiğinde (Esc, Ctrl+C) süren istekler de iptal edilir.
 type AnimeSource interface {
 	// Arama sorgusuna göre anime verilerini getirir.
-	GetSearchData(query string) ([]Anime, error)
+	GetSearchData(ctx context.Context, query string) ([]Anime, error)
 	// Sezon verilerini getirir.
-	GetSeasonsData(params SeasonParams) ([]Season, error)
-	// Bölüm verilerini getirir.
-	GetEpisodesData(params EpisodeParams) ([]Episode, error)
-	// İzleme verilerini getirir.
-	GetWatchData(params WatchParams) ([]Watch, error)
+	GetSeasonsData(ctx context.Context, params SeasonParams) ([]Season, error)
+	// Bölüm verilerini getirir. Bazı sezonlar alınamazsa diğer sezonların bölümleri
+	// *SeasonsError ile birlikte döner.
+	GetEpisodesData(ctx context.Context, params EpisodeParams) ([]Episode, error)
+	// Bölümün (veya filmin) oynatılabilir video akışlarını getirir.
+	GetWatchData(ctx context.Context, params WatchParams) ([]Stream, error)
 	// Kaynağın adını döner.
 	Source() string
 }
 
+// FansubSource arayüzü, fansub seçimini destekleyen kaynaklar tarafından uygulanır.
+type FansubSource interface {
+	// Bölüm için fansub verilerini getirir.
+	GetFansubsData(ctx context.Context, params FansubParams) ([]Fansub, error)
+}
+
 // Anime yapısı, bir anime hakkında temel bilgileri içerir.
 type Anime struct {
 	Title     string                 // Anime başlığı
@@ -37,10 +54,38 @@ type Season struct {
 
 // Episode yapısı, bir anime bölümünün bilgilerini içerir.
 type Episode struct {
-	ID     string                 // Bölüm ID'si
-	Title  string                 // Bölüm başlığı
-	Number int                    // Bölüm numarası
-	Extra  map[string]interface{} // Ekstra veriler
+	ID             string                 // Bölüm ID'si
+	Title          string                 // Bölüm başlığı
+	Season         int                    // Sezon numarası (1'den başlar)
+	Number         int                    // Sezon içindeki bölüm numarası
+	AbsoluteNumber int                    // Tüm sezonlar boyunca bölümün sırası (1'den başlar)
+	AirDate        time.Time              // Yayın tarihi (bilinmiyorsa sıfır değer)
+	Duration       time.Duration          // Bölüm süresi (bilinmiyorsa 0)
+	Extra          map[string]interface{} // Yalnızca kaynağa özgü ekstra veriler
+}
+
+// SeasonsError, bazı sezonların bölümleri alınamadığında döner. Hata GetEpisodesData'nın
+// alınabilen sezonların bölümleriyle birlikte döndürdüğü kısmi sonucu işaretler.
+type SeasonsError struct {
+	Failed map[int]error // Alınamayan sezon numarası -> hata
+}
+
+// Seasons, alınamayan sezonların numaralarını sıralı olarak döner.
+func (e *SeasonsError) Seasons() []int {
+	seasons := make([]int, 0, len(e.Failed))
+	for season := range e.Failed {
+		seasons = append(seasons, season)
+	}
+	sort.Ints(seasons)
+	return seasons
+}
+
+func (e *SeasonsError) Error() string {
+	parts := make([]string, 0, len(e.Failed))
+	for _, season := range e.Seasons() {
+		parts = append(parts, fmt.Sprintf("sezon %d: %v", season, e.Failed[season]))
+	}
+	return "bazı sezonlar alınamadı: " + strings.Join(parts, "; ")
 }
 
 // Fansub yapısı, bir anime için Türkçe altyazı ekleyen grup hakkında bilgileri içerir.
@@ -52,11 +97,11 @@ type Fansub struct {
 
 // WatchParams yapısı, izleme işlemi için gerekli parametreleri içerir.
 type WatchParams struct {
-	Slug    *string                 // Anime URL dostu adı (nullable)
-	Url     *string                 // İzleme URL'si (nullable)
-	Id      *int                    // Anime ID'si (nullable)
-	IsMovie *bool                   // Anime'nin film olup olmadığı (nullable)
-	Extra   *map[string]interface{} // Ekstra bilgiler (nullable)
+	Slug     *string  // Anime URL dostu adı (nullable)
+	Id       *int     // Anime ID'si (nullable)
+	IsMovie  *bool    // Anime'nin film olup olmadığı (nullable)
+	Episode  *Episode // İzlenecek bölüm (film için nil olabilir)
+	FansubID *string  // Tercih edilen fansub ID'si (nullable, yoksa ilk fansub kullanılır)
 }
 
 // FansubParams yapısı, bir bölüm için fansub verilerini filtrelemek amacıyla parametreleri içerir.
@@ -79,9 +124,61 @@ type EpisodeParams struct {
 	SeasonID *int    // Sezon ID'si (nullable)
 }
 
-// Watch yapısı, bir anime'yi izlerken gerekli olan izleme bilgilerini içerir.
-type Watch struct {
-	Labels    []string // Etiketler ("1080", "720p", vb.)
-	Urls      []string // İzleme URL'leri
-	TRCaption *string  // Türkçe altyazı (nullable)
+// Stream yapısı, oynatılabilir veya indirilebilir tek bir video akışını temsil eder.
+type Stream struct {
+	Quality   string            // Çözünürlük etiketi ("1080p", "720p" vb.)
+	URL       string            // Video URL'si
+	Headers   map[string]string // Akışa erişirken gönderilmesi gereken HTTP başlıkları (Referer, User-Agent vb.)
+	Subtitles []Subtitle        // Akışla birlikte kullanılabilecek altyazılar
+}
+
+// Subtitle yapısı, bir altyazı izini temsil eder.
+type Subtitle struct {
+	Language string // Altyazı dili ("tr" vb.)
+	URL      string // Altyazı dosyasının URL'si
+}
+
+// QualityValue, çözünürlük etiketini sıralama için sayıya çevirir ("1080p" -> 1080).
+// Etiket sayı içermiyorsa 0 döner.
+func (s Stream) QualityValue() int {
+	n, _ := strconv.Atoi(strings.TrimRight(strings.TrimSpace(s.Quality), "pP"))
+	return n
+}
+
+// SubtitleURL, akışın ilk altyazısının URL'sini döner. Altyazı yoksa boş string döner.
+func (s Stream) SubtitleURL() string {
+	if len(s.Subtitles) == 0 {
+		return ""
+	}
+	return s.Subtitles[0].URL
+}
+
+// SortStreams, akışları çözünürlüğe göre yüksekten düşüğe sıralar.
+func SortStreams(streams []Stream) {
+	sort.SliceStable(streams, func(i, j int) bool {
+		return streams[i].QualityValue() > streams[j].QualityValue()
+	})
+}
+
+// SelectStream, verilen çözünürlük etiketine sahip akışı döner.
+// Eşleşme yoksa ilk akış döner; ikinci değer tam eşleşme olup olmadığını bildirir.
+func SelectStream(streams []Stream, quality string) (Stream, bool) {
+	for _, s := range streams {
+		if s.Quality == quality {
+			return s, true
+		}
+	}
+	if len(streams) == 0 {
+		return Stream{}, false
+	}
+	return streams[0], false
+}
+
+// Qualities, akışların çözünürlük etiketlerini sırasıyla döner.
+func Qualities(streams []Stream) []string {
+	labels := make([]string, 0, len(streams))
+	for _, s := range streams {
+		labels = append(labels, s.Quality)
+	}
+	return labels
 }
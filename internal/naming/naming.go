@@ -0,0 +1,185 @@
+// naming paketi, indirilen dosyaların adlarını şablonlardan oluşturur ve dosya sistemi
+// için güvenli hâle getirir.
+//
+// Şablonlarda {ad} veya sayılar için {ad:02} (sıfırla doldurulmuş genişlik) biçiminde
+// yer tutucular kullanılır. Şablondaki "/" alt dizin oluşturur; yer tutucu değerlerindeki
+// "/" ise dizin ayırıcı olarak yorumlanmaz.
+package naming
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultTemplate, dizi bölümleri için varsayılan şablondur.
+	DefaultTemplate = "{anime}/{title}.{ext}"
+	// DefaultMovieTemplate, filmler için varsayılan şablondur.
+	DefaultMovieTemplate = "{anime}/{anime}.{ext}"
+)
+
+// Fields, şablondaki yer tutucuların değerleridir.
+type Fields struct {
+	Anime    string // {anime}: anime adı
+	Title    string // {title}: bölüm başlığı
+	Season   int    // {season}: sezon numarası
+	Episode  int    // {episode}: sezon içindeki bölüm numarası
+	Absolute int    // {absolute}: tüm sezonlar boyunca bölüm sırası
+	Fansub   string // {fansub}: fansub adı
+	Quality  string // {quality}: çözünürlük etiketi
+	Source   string // {source}: kaynağın adı
+	Ext      string // {ext}: dosya uzantısı (noktasız)
+}
+
+// Placeholders, desteklenen yer tutucuların adlarıdır.
+var Placeholders = []string{"anime", "title", "season", "episode", "absolute", "fansub", "quality", "source", "ext"}
+
+var placeholder = regexp.MustCompile(`\{([a-z_]+)(?::(\d+))?\}`)
+
+// Validate, şablonun yalnızca desteklenen yer tutucuları içerdiğini ve dosya adı ürettiğini kontrol eder.
+func Validate(template string) error {
+	if strings.TrimSpace(template) == "" {
+		return fmt.Errorf("şablon boş olamaz")
+	}
+	_, err := Render(template, Fields{Anime: "a", Title: "b", Ext: "mp4"})
+	return err
+}
+
+// Render, şablondan göreli bir dosya yolu üretir. Her yol parçası işletim sistemine göre
+// güvenli hâle getirilir; boş kalan köşeli/normal parantezler (örn. fansub yoksa "[]") silinir.
+func Render(template string, f Fields) (string, error) {
+	var renderErr error
+	rendered := placeholder.ReplaceAllStringFunc(template, func(m string) string {
+		sub := placeholder.FindStringSubmatch(m)
+		name, width := sub[1], sub[2]
+
+		var value string
+		switch name {
+		case "anime":
+			value = f.Anime
+		case "title":
+			value = f.Title
+		case "fansub":
+			value = f.Fansub
+		case "quality":
+			value = f.Quality
+		case "source":
+			value = f.Source
+		case "ext":
+			value = f.Ext
+		case "season", "episode", "absolute":
+			n := map[string]int{"season": f.Season, "episode": f.Episode, "absolute": f.Absolute}[name]
+			if width != "" {
+				w, _ := strconv.Atoi(width)
+				return fmt.Sprintf("%0*d", w, n)
+			}
+			return strconv.Itoa(n)
+		default:
+			renderErr = fmt.Errorf("bilinmeyen yer tutucu: {%s} (kullanılabilir: %s)", name, strings.Join(Placeholders, ", "))
+			return m
+		}
+
+		// Değerlerdeki ayırıcılar alt dizin oluşturmasın
+		return strings.NewReplacer("/", "-", `\`, "-").Replace(value)
+	})
+	if renderErr != nil {
+		return "", renderErr
+	}
+
+	var parts []string
+	for _, part := range strings.Split(rendered, "/") {
+		part = Sanitize(removeEmptyBrackets(part))
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return "", fmt.Errorf("şablon boş bir dosya adı üretti: %s", template)
+	}
+	return filepath.Join(parts...), nil
+}
+
+var (
+	emptyBrackets = regexp.MustCompile(`\[\s*\]|\(\s*\)`)
+	spaces        = regexp.MustCompile(`\s{2,}`)
+	danglingExt   = regexp.MustCompile(`[\s-]+(\.[A-Za-z0-9]+)$`)
+)
+
+// removeEmptyBrackets, değeri boş kalan yer tutuculardan arta kalan "[]", "()", fazla boşlukları
+// ve uzantıdan önce kalan " -" parçalarını temizler.
+func removeEmptyBrackets(s string) string {
+	s = emptyBrackets.ReplaceAllString(s, "")
+	s = spaces.ReplaceAllString(s, " ")
+	s = danglingExt.ReplaceAllString(s, "$1")
+	return strings.Trim(s, " -")
+}
+
+// windowsReserved, Windows'ta dosya adı olarak kullanılamayan aygıt adlarıdır.
+var windowsReserved = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// Sanitize, tek bir yol parçasını çalışılan işletim sisteminde geçerli bir dosya adına çevirir.
+func Sanitize(name string) string {
+	return sanitize(name, runtime.GOOS)
+}
+
+// sanitize, yol parçasını goos için güvenli hâle getirir. Windows'ta <>:"/\|?* karakterleri,
+// sondaki nokta ve boşluklar ile ayrılmış aygıt adları; diğer sistemlerde "/" ve NUL temizlenir.
+func sanitize(name, goos string) string {
+	if goos == "windows" {
+		// "Bölüm 1: Başlangıç" -> "Bölüm 1 - Başlangıç", "Re:Zero" -> "Re-Zero"
+		name = strings.ReplaceAll(name, ": ", " - ")
+	}
+
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r < 0x20 || r == 0x7f:
+			// Kontrol karakterleri atılır
+		case r == '/':
+			b.WriteRune('-')
+		case goos == "windows" && (r == '\\' || r == '|' || r == ':'):
+			b.WriteRune('-')
+		case goos == "windows" && r == '"':
+			b.WriteRune('\'')
+		case goos == "windows" && strings.ContainsRune("<>?*", r):
+			// Karşılığı olmayan karakterler atılır
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	s := spaces.ReplaceAllString(b.String(), " ")
+	s = strings.TrimSpace(s)
+	if goos == "windows" {
+		s = strings.TrimRight(s, ". ")
+		base, _, _ := strings.Cut(s, ".")
+		if windowsReserved[strings.ToUpper(base)] {
+			s = "_" + s
+		}
+	}
+	if s == "." || s == ".." {
+		return ""
+	}
+
+	// Çoğu dosya sistemi 255 baytlık ad sınırı uygular; uzantı korunarak kısaltılır
+	if len(s) > 255 {
+		ext := filepath.Ext(s)
+		if len(ext) > 16 {
+			ext = ""
+		}
+		base := []rune(strings.TrimSuffix(s, ext))
+		for len(string(base))+len(ext) > 255 {
+			base = base[:len(base)-1]
+		}
+		s = strings.TrimSpace(string(base)) + ext
+	}
+	return s
+}
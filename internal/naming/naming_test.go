@@ -0,0 +1,151 @@
+package naming
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRender(t *testing.T) {
+	episode := Fields{Anime: "One Piece", Title: "Bölüm 5", Season: 1, Episode: 5, Absolute: 7, Quality: "1080p", Source: "animecix", Ext: "mp4"}
+
+	tests := []struct {
+		name     string
+		template string
+		fields   Fields
+		want     string
+		wantErr  bool
+	}{
+		{name: "varsayılan şablon", template: DefaultTemplate, fields: episode, want: "One Piece/Bölüm 5.mp4"},
+		{name: "varsayılan film şablonu", template: DefaultMovieTemplate, fields: Fields{Anime: "Kimi no Na wa", Ext: "mkv"}, want: "Kimi no Na wa/Kimi no Na wa.mkv"},
+		{
+			name:     "sıfırla doldurma",
+			template: "{anime}/Season {season:02}/S{season:02}E{episode:02} ({absolute:03}).{ext}",
+			fields:   episode,
+			want:     "One Piece/Season 01/S01E05 (007).mp4",
+		},
+		{name: "genişliksiz sayı", template: "{episode}-{absolute}.{ext}", fields: episode, want: "5-7.mp4"},
+		{
+			name:     "boş fansub parantezi silinir",
+			template: "{anime} - {episode:02} [{fansub}].{ext}",
+			fields:   episode,
+			want:     "One Piece - 05.mp4",
+		},
+		{
+			name:     "dolu fansub korunur",
+			template: "{anime} - {episode:02} [{fansub}].{ext}",
+			fields:   Fields{Anime: "One Piece", Episode: 5, Fansub: "TR Fansub", Ext: "mp4"},
+			want:     "One Piece - 05 [TR Fansub].mp4",
+		},
+		{name: "boş normal parantez", template: "{anime} ({quality}).{ext}", fields: Fields{Anime: "A", Ext: "mp4"}, want: "A.mp4"},
+		{name: "uzantıdan önce kalan tire", template: "{anime} - {fansub}.{ext}", fields: Fields{Anime: "A", Ext: "mp4"}, want: "A.mp4"},
+		{name: "değerdeki / alt dizin oluşturmaz", template: DefaultTemplate, fields: Fields{Anime: "Fate/Zero", Title: `a\b`, Ext: "mp4"}, want: "Fate-Zero/a-b.mp4"},
+		{name: "şablondaki .. atlanır", template: "{anime}/../{title}.{ext}", fields: episode, want: "One Piece/Bölüm 5.mp4"},
+		{name: "boş yol parçaları atlanır", template: "{source}//{fansub}/{title}.{ext}", fields: episode, want: "animecix/Bölüm 5.mp4"},
+		{name: "bilinmeyen yer tutucu", template: "{anime}/{bolum}.{ext}", fields: episode, wantErr: true},
+		{name: "boş dosya adı", template: "{fansub}", fields: episode, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.template, tt.fields)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render(%q) hatası = %v, beklenen hata: %v", tt.template, err, tt.wantErr)
+			}
+			if want := filepath.FromSlash(tt.want); !tt.wantErr && got != want {
+				t.Fatalf("Render(%q) = %q, beklenen %q", tt.template, got, want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		template string
+		wantErr  bool
+	}{
+		{DefaultTemplate, false},
+		{"{anime}/S{season:02}E{episode:02}.{ext}", false},
+		{"", true},
+		{"   ", true},
+		{"{anime}/{unknown}.{ext}", true},
+		{"{fansub}", true},
+	}
+	for _, tt := range tests {
+		if err := Validate(tt.template); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%q) = %v", tt.template, err)
+		}
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name string
+		goos string
+		in   string
+		want string
+	}{
+		{"linux: ayırıcı", "linux", "a/b", "a-b"},
+		{"linux: kontrol karakterleri", "linux", "a\x00b\tc\x7f", "abc"},
+		{"linux: iki nokta korunur", "linux", "Re:Zero", "Re:Zero"},
+		{"linux: ayrılmış ad serbest", "linux", "CON", "CON"},
+		{"linux: fazla boşluk", "linux", "  a   b  ", "a b"},
+		{"linux: nokta", "linux", ".", ""},
+		{"linux: üst dizin", "linux", "..", ""},
+		{"windows: iki nokta ve boşluk", "windows", "Bölüm 1: Başlangıç", "Bölüm 1 - Başlangıç"},
+		{"windows: iki nokta", "windows", "Re:Zero", "Re-Zero"},
+		{"windows: ters bölü ve dikey çizgi", "windows", `a\b|c`, "a-b-c"},
+		{"windows: çift tırnak", "windows", `"Ano Hi"`, "'Ano Hi'"},
+		{"windows: karşılıksız karakterler", "windows", "a<b>c?d*e", "abcde"},
+		{"windows: sondaki nokta ve boşluk", "windows", "Ne? . . ", "Ne"},
+		{"windows: CON", "windows", "CON", "_CON"},
+		{"windows: küçük harfli ve uzantılı", "windows", "con.txt", "_con.txt"},
+		{"windows: COM1", "windows", "COM1.tar.gz", "_COM1.tar.gz"},
+		{"windows: LPT9", "windows", "lpt9", "_lpt9"},
+		{"windows: ayrılmış ad öneki", "windows", "CONSOLE.mp4", "CONSOLE.mp4"},
+		{"windows: ayrılmış olmayan COM0", "windows", "COM0", "COM0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitize(tt.in, tt.goos); got != tt.want {
+				t.Fatalf("sanitize(%q, %s) = %q, beklenen %q", tt.in, tt.goos, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeTruncate(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		wantExt string
+	}{
+		{"ASCII", strings.Repeat("a", 300) + ".mp4", ".mp4"},
+		{"çok baytlı karakterler bölünmez", strings.Repeat("ş", 200) + ".mkv", ".mkv"},
+		{"uzantı yok", strings.Repeat("ğ", 200), ""},
+		{"uzun uzantı korunmaz", strings.Repeat("a", 250) + "." + strings.Repeat("b", 20), ""},
+		{"sınırda", strings.Repeat("a", 251) + ".mp4", ".mp4"},
+	}
+	for _, tt := range tests {
+		for _, goos := range []string{"linux", "windows"} {
+			t.Run(tt.name+"/"+goos, func(t *testing.T) {
+				got := sanitize(tt.in, goos)
+				if len(got) > 255 {
+					t.Fatalf("ad %d bayt, sınır 255", len(got))
+				}
+				if len(tt.in) <= 255 && got != tt.in {
+					t.Fatalf("sınırdaki ad değişti: %q", got)
+				}
+				if !utf8.ValidString(got) {
+					t.Fatalf("ad geçerli UTF-8 değil: %q", got)
+				}
+				if filepath.Ext(got) != tt.wantExt && tt.wantExt != "" {
+					t.Fatalf("uzantı %q, beklenen %q", filepath.Ext(got), tt.wantExt)
+				}
+				if len(got) < 250 {
+					t.Fatalf("ad gereğinden fazla kısaltıldı: %d bayt", len(got))
+				}
+			})
+		}
+	}
+}
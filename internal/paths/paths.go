@@ -0,0 +1,181 @@
+// paths paketi, uygulamanın yapılandırma, veri, önbellek, durum ve indirme dizinlerini
+// işletim sistemine uygun şekilde belirler.
+//
+// Linux ve diğer Unix sistemlerde XDG Base Directory değişkenleri ($XDG_CONFIG_HOME,
+// $XDG_DATA_HOME, $XDG_CACHE_HOME, $XDG_STATE_HOME) kullanılır. Windows'ta yapılandırma
+// %APPDATA%, diğer dizinler %LOCALAPPDATA% altında tutulur.
+package paths
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// AppName, tüm dizinlerin altında kullanılan uygulama klasörünün adıdır.
+const AppName = "anitr-cli"
+
+// ConfigDir, yapılandırma dizinini döner.
+func ConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("yapılandırma dizini bulunamadı: %w", err)
+	}
+	return filepath.Join(dir, AppName), nil
+}
+
+// DataDir, izleme geçmişi gibi kalıcı verilerin dizinini döner.
+func DataDir() (string, error) {
+	return baseDir("XDG_DATA_HOME", filepath.Join(".local", "share"), "data")
+}
+
+// CacheDir, silinmesi sorun olmayan önbellek dosyalarının dizinini döner.
+func CacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("önbellek dizini bulunamadı: %w", err)
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(dir, AppName, "cache"), nil
+	}
+	return filepath.Join(dir, AppName), nil
+}
+
+// StateDir, log dosyaları gibi durum verilerinin dizinini döner.
+func StateDir() (string, error) {
+	return baseDir("XDG_STATE_HOME", filepath.Join(".local", "state"), "state")
+}
+
+// DownloadDir, indirilen bölümlerin varsayılan kök dizinini döner.
+// Linux'ta xdg-user-dirs'in XDG_DOWNLOAD_DIR ayarı (örn. ~/İndirilenler), yoksa ~/Downloads kullanılır.
+func DownloadDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("ev dizini bulunamadı: %w", err)
+	}
+
+	dir := os.Getenv("XDG_DOWNLOAD_DIR")
+	if dir == "" && runtime.GOOS != "windows" && runtime.GOOS != "darwin" {
+		dir = userDirsDownload(home)
+	}
+	if dir == "" {
+		dir = filepath.Join(home, "Downloads")
+	}
+	return filepath.Join(dir, AppName), nil
+}
+
+// Ensure, dizini (gerekirse üst dizinleriyle birlikte) oluşturur ve yolunu döner.
+func Ensure(dir string, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("dizin oluşturulamadı: %w", err)
+	}
+	return dir, nil
+}
+
+// baseDir, XDG değişkenine göre uygulamanın dizinini döner. Değişken tanımlı değilse
+// Unix'te ev dizinine göre unixDefault, Windows'ta %LOCALAPPDATA%\anitr-cli\<windowsSub> kullanılır.
+func baseDir(xdgEnv, unixDefault, windowsSub string) (string, error) {
+	if runtime.GOOS == "windows" {
+		dir := os.Getenv("LOCALAPPDATA")
+		if dir == "" {
+			return "", errors.New("%LOCALAPPDATA% tanımlı değil")
+		}
+		return filepath.Join(dir, AppName, windowsSub), nil
+	}
+
+	// XDG belirtimine göre göreli yollar yok sayılır
+	if dir := os.Getenv(xdgEnv); filepath.IsAbs(dir) {
+		return filepath.Join(dir, AppName), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("ev dizini bulunamadı: %w", err)
+	}
+	return filepath.Join(home, unixDefault, AppName), nil
+}
+
+// userDirsDownload, ~/.config/user-dirs.dirs dosyasındaki XDG_DOWNLOAD_DIR değerini okur.
+func userDirsDownload(home string) string {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if !filepath.IsAbs(configHome) {
+		configHome = filepath.Join(home, ".config")
+	}
+
+	f, err := os.Open(filepath.Join(configHome, "user-dirs.dirs"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		value, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "XDG_DOWNLOAD_DIR=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"`)
+		value = strings.Replace(value, "$HOME", home, 1)
+		// Yalnızca ev dizinine ayarlanmışsa (xdg-user-dirs'te "devre dışı" anlamına gelir) kullanılmaz
+		if filepath.Clean(value) == filepath.Clean(home) {
+			return ""
+		}
+		return value
+	}
+	return ""
+}
+
+// MigrateFile, eski konumdaki dosyayı yeni konuma bir kereliğine taşır.
+// Yeni konumda dosya zaten varsa veya eski dosya yoksa hiçbir şey yapılmaz;
+// taşıma yapıldıysa true döner.
+func MigrateFile(oldPath, newPath string) (bool, error) {
+	if _, err := os.Stat(newPath); err == nil {
+		return false, nil
+	}
+	if _, err := os.Stat(oldPath); err != nil {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return false, fmt.Errorf("dizin oluşturulamadı: %w", err)
+	}
+
+	// Farklı disklerde Rename başarısız olur, bu durumda kopyalanıp silinir
+	if err := os.Rename(oldPath, newPath); err == nil {
+		return true, nil
+	}
+	if err := copyFile(oldPath, newPath); err != nil {
+		return false, err
+	}
+	if err := os.Remove(oldPath); err != nil {
+		return true, fmt.Errorf("eski dosya silinemedi: %w", err)
+	}
+	return true, nil
+}
+
+// copyFile, dosyayı yeni konuma kopyalar.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
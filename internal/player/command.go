@@ -0,0 +1,107 @@
+package player
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Command, kullanıcı tanımlı bir komut şablonuyla çalışan oynatıcıdır.
+//
+// Şablondaki yer tutucular: {url}, {title}, {subtitle}, {start}, {referer}, {user_agent}.
+// Şablon önce argümanlara bölünür, ardından yer tutucular doldurulur; böylece boşluk
+// içeren değerler tek argüman olarak kalır. Boş kalan argümanlar atılır ve şablonda
+// {url} yoksa URL son argüman olarak eklenir.
+//
+// Örnek: `celluloid --mpv-start={start} {url}`
+type Command struct {
+	Template string // Komut şablonu
+}
+
+// Name, oynatıcının adını döner.
+func (c Command) Name() string {
+	return "custom"
+}
+
+// Play, şablondaki komutu verilen parametrelerle çalıştırır.
+func (c Command) Play(params Params) (*Session, error) {
+	fields, err := splitArgs(c.Template)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("özel oynatıcı komutu boş")
+	}
+
+	startTime := ""
+	if params.StartTime > 0 {
+		startTime = strconv.Itoa(int(params.StartTime))
+	}
+	replacer := strings.NewReplacer(
+		"{url}", params.URL,
+		"{title}", params.Title,
+		"{subtitle}", params.SubtitleURL,
+		"{start}", startTime,
+		"{referer}", header(params.Headers, "Referer"),
+		"{user_agent}", header(params.Headers, "User-Agent"),
+	)
+
+	hasURL := false
+	args := make([]string, 0, len(fields))
+	for _, field := range fields[1:] {
+		if strings.Contains(field, "{url}") {
+			hasURL = true
+		}
+		arg := replacer.Replace(field)
+		if arg == "" {
+			continue
+		}
+		args = append(args, arg)
+	}
+	if !hasURL {
+		args = append(args, params.URL)
+	}
+
+	return start(fields[0], fields[0], args)
+}
+
+// splitArgs, komut satırını kabuk benzeri kurallarla argümanlara böler.
+// Tek ve çift tırnak içindeki boşluklar argümanı bölmez.
+func splitArgs(s string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		quote   rune
+		inArg   bool
+	)
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("komut şablonunda kapatılmamış tırnak var")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
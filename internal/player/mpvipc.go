@@ -0,0 +1,259 @@
+package player
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/xeyossr/anitr-cli/internal/ipc"
+)
+
+// Event, oynatıcıdan gelen bir olaydır.
+type Event struct {
+	Name   string // Olay adı ("end-file", "file-loaded", "pause", "seek" vb.)
+	Reason string // end-file olayında bitiş nedeni ("eof", "stop", "quit", "error")
+	Paused bool   // pause olayında oynatmanın duraklatılıp duraklatılmadığı
+}
+
+// pauseObserverID, "pause" özelliğini izlemek için kullanılan gözlemci kimliğidir.
+const pauseObserverID = 1
+
+// ipcMessage, mpv'nin IPC üzerinden gönderdiği yanıt ve olayların ortak biçimidir.
+type ipcMessage struct {
+	RequestID int             `json:"request_id"`
+	Error     string          `json:"error"`
+	Data      json.RawMessage `json:"data"`
+	Event     string          `json:"event"`
+	Reason    string          `json:"reason"`
+	ID        int             `json:"id"`
+	Name      string          `json:"name"`
+}
+
+// IPCClient, mpv'nin JSON IPC arayüzüne bağlı bir istemcidir.
+type IPCClient struct {
+	conn    net.Conn
+	writeMu sync.Mutex // Komutların soket üzerinde karışmasını önler
+	mu      sync.Mutex // nextID ve pending alanlarını korur
+	nextID  int
+	pending map[int]chan ipcMessage
+	events  chan Event
+	closed  chan struct{}
+	once    sync.Once
+}
+
+// mpvSocketPath, mpv IPC sunucusu için platforma uygun benzersiz bir yol üretir.
+func mpvSocketPath() string {
+	name := fmt.Sprintf("anitr-cli-mpv-%d-%d", os.Getpid(), time.Now().UnixNano())
+	if runtime.GOOS == "windows" {
+		return `\\.\pipe\` + name
+	}
+	return filepath.Join(os.TempDir(), name+".sock")
+}
+
+// DialIPC, verilen soket yoluna bağlanır. mpv soketi açılışta biraz geç oluşturduğundan
+// bağlantı timeout süresi boyunca tekrar denenir.
+func DialIPC(path string, timeout time.Duration) (*IPCClient, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := ipc.ConnectToPipe(path)
+		if err == nil {
+			client := &IPCClient{
+				conn:    conn,
+				pending: make(map[int]chan ipcMessage),
+				events:  make(chan Event, 16),
+				closed:  make(chan struct{}),
+			}
+			go client.readLoop()
+			return client, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, err
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// readLoop, mpv'den gelen satırları okuyup yanıtları bekleyen komutlara, olayları kanala iletir.
+func (c *IPCClient) readLoop() {
+	defer close(c.events)
+	defer c.Close()
+
+	scanner := bufio.NewScanner(c.conn)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var msg ipcMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			continue
+		}
+
+		if msg.Event == "" {
+			c.mu.Lock()
+			ch, ok := c.pending[msg.RequestID]
+			delete(c.pending, msg.RequestID)
+			c.mu.Unlock()
+			if ok {
+				ch <- msg
+			}
+			continue
+		}
+
+		event := Event{Name: msg.Event, Reason: msg.Reason}
+		if msg.Event == "property-change" {
+			if msg.ID != pauseObserverID {
+				continue
+			}
+			event = Event{Name: "pause"}
+			_ = json.Unmarshal(msg.Data, &event.Paused)
+		}
+
+		// Olayları okuyan yoksa okuma döngüsü tıkanmasın diye olay atlanır
+		select {
+		case c.events <- event:
+		default:
+		}
+	}
+}
+
+// Events, oynatıcı olaylarının kanalını döner. Bağlantı kapanınca kanal kapanır.
+func (c *IPCClient) Events() <-chan Event {
+	return c.events
+}
+
+// Close, IPC bağlantısını kapatır.
+func (c *IPCClient) Close() error {
+	var err error
+	c.once.Do(func() {
+		close(c.closed)
+		err = c.conn.Close()
+	})
+	return err
+}
+
+// Command, mpv'ye bir komut gönderir ve yanıtın data alanını döner.
+func (c *IPCClient) Command(args ...interface{}) (json.RawMessage, error) {
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+	ch := make(chan ipcMessage, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	payload, err := json.Marshal(map[string]interface{}{"command": args, "request_id": id})
+	if err != nil {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		return nil, err
+	}
+
+	c.writeMu.Lock()
+	_, err = c.conn.Write(append(payload, '\n'))
+	c.writeMu.Unlock()
+	if err != nil {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		return nil, fmt.Errorf("mpv komutu gönderilemedi: %w", err)
+	}
+
+	select {
+	case msg := <-ch:
+		if msg.Error != "success" {
+			return nil, fmt.Errorf("mpv komutu başarısız (%v): %s", args[0], msg.Error)
+		}
+		return msg.Data, nil
+	case <-c.closed:
+		return nil, errors.New("mpv bağlantısı kapandı")
+	case <-time.After(5 * time.Second):
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		return nil, errors.New("mpv yanıt vermedi")
+	}
+}
+
+// getFloat, sayısal bir mpv özelliğini okur.
+func (c *IPCClient) getFloat(name string) (float64, error) {
+	data, err := c.Command("get_property", name)
+	if err != nil {
+		return 0, err
+	}
+	var value float64
+	if err := json.Unmarshal(data, &value); err != nil {
+		return 0, fmt.Errorf("%s özelliği okunamadı: %w", name, err)
+	}
+	return value, nil
+}
+
+// Position, oynatma konumunu saniye cinsinden döner.
+func (c *IPCClient) Position() (float64, error) {
+	return c.getFloat("time-pos")
+}
+
+// Duration, videonun süresini saniye cinsinden döner.
+func (c *IPCClient) Duration() (float64, error) {
+	return c.getFloat("duration")
+}
+
+// Paused, oynatmanın duraklatılıp duraklatılmadığını döner.
+func (c *IPCClient) Paused() (bool, error) {
+	data, err := c.Command("get_property", "pause")
+	if err != nil {
+		return false, err
+	}
+	var paused bool
+	err = json.Unmarshal(data, &paused)
+	return paused, err
+}
+
+// SetPause, oynatmayı duraklatır veya devam ettirir.
+func (c *IPCClient) SetPause(paused bool) error {
+	_, err := c.Command("set_property", "pause", paused)
+	return err
+}
+
+// SetTitle, oynatıcıda gösterilen medya başlığını değiştirir.
+func (c *IPCClient) SetTitle(title string) error {
+	_, err := c.Command("set_property", "force-media-title", title)
+	return err
+}
+
+// observePause, "pause" özelliğindeki değişikliklerin olay olarak gönderilmesini sağlar.
+func (c *IPCClient) observePause() error {
+	_, err := c.Command("observe_property", pauseObserverID, "pause")
+	return err
+}
+
+// pollMPVStatus, mpv kapanana kadar oynatma konumunu saniyede bir okur.
+func pollMPVStatus(session *Session, client *IPCClient) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-session.done:
+			return
+		case <-client.closed:
+			return
+		case <-ticker.C:
+		}
+
+		// Dosya yüklenirken özellikler henüz mevcut olmayabilir, hatalar yok sayılır
+		position, err := client.Position()
+		if err != nil {
+			continue
+		}
+		duration, err := client.Duration()
+		if err != nil || duration <= 0 {
+			continue
+		}
+		session.update(PlaybackStatus{Position: position, Duration: duration})
+	}
+}
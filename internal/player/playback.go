@@ -0,0 +1,77 @@
+package player
+
+import (
+	"os/exec"
+	"sync"
+)
+
+// PlaybackStatus, oynatıcının bildirdiği oynatma konumunu tutar.
+type PlaybackStatus struct {
+	Position float64 // Oynatma konumu (saniye)
+	Duration float64 // Video süresi (saniye, bilinmiyorsa 0)
+}
+
+// Known, oynatıcıdan geçerli bir konum alınıp alınmadığını bildirir.
+func (s PlaybackStatus) Known() bool {
+	return s.Duration > 0
+}
+
+// Percent, oynatma konumunu yüzde olarak döner. Süre bilinmiyorsa 0 döner.
+func (s PlaybackStatus) Percent() float64 {
+	if !s.Known() {
+		return 0
+	}
+	return s.Position / s.Duration * 100
+}
+
+// Session, çalışan bir oynatıcı sürecini ve son bilinen oynatma konumunu tutar.
+type Session struct {
+	cmd    *exec.Cmd
+	done   chan struct{}
+	mu     sync.Mutex
+	status PlaybackStatus
+	ipc    *IPCClient // Oynatıcı IPC desteklemiyorsa nil
+}
+
+// newSession, başlatılmış oynatıcı süreci için bir oturum oluşturur.
+func newSession(cmd *exec.Cmd) *Session {
+	return &Session{cmd: cmd, done: make(chan struct{})}
+}
+
+// update, son bilinen oynatma konumunu günceller.
+func (s *Session) update(status PlaybackStatus) {
+	s.mu.Lock()
+	s.status = status
+	s.mu.Unlock()
+}
+
+// Status, son bilinen oynatma konumunu döner.
+func (s *Session) Status() PlaybackStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// Controller, oynatıcıyı kontrol etmek için IPC istemcisini döner.
+// Oynatıcı IPC desteklemiyorsa veya bağlantı kurulamadıysa nil döner.
+func (s *Session) Controller() *IPCClient {
+	return s.ipc
+}
+
+// Events, oynatıcı olaylarının kanalını döner. IPC yoksa nil döner.
+func (s *Session) Events() <-chan Event {
+	if s.ipc == nil {
+		return nil
+	}
+	return s.ipc.Events()
+}
+
+// Wait, oynatıcı kapanana kadar bekler ve kapanmadan önceki son konumu döner.
+func (s *Session) Wait() (PlaybackStatus, error) {
+	err := s.cmd.Wait()
+	close(s.done)
+	if s.ipc != nil {
+		s.ipc.Close()
+	}
+	return s.Status(), err
+}
@@ -0,0 +1,84 @@
+// player paketi, videoları harici oynatıcılarla (VLC, mpv, IINA veya özel komut) oynatır.
+package player
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// Params, tüm oynatıcılara verilen ortak oynatma parametrelerini tutar.
+type Params struct {
+	URL         string            // Oynatılacak video URL'si
+	Title       string            // Pencere / medya başlığı
+	SubtitleURL string            // Altyazı URL'si (boşsa altyazı yok)
+	Headers     map[string]string // Akış için gönderilecek HTTP başlıkları (Referer, User-Agent vb.)
+	StartTime   float64           // Oynatmanın başlayacağı konum (saniye, 0 ise baştan)
+}
+
+// Player arayüzü, bir oynatıcı uygulamasını başlatma işlevini tanımlar.
+type Player interface {
+	// Oynatıcının adını döner.
+	Name() string
+	// Videoyu oynatıcıda başlatır.
+	Play(params Params) (*Session, error)
+}
+
+// factories, oynatıcı adlarını yapıcı fonksiyonlarıyla eşler.
+// path boşsa oynatıcının varsayılan çalıştırılabilir dosyası kullanılır.
+var factories = map[string]func(path, command string) (Player, error){
+	"vlc":  func(path, _ string) (Player, error) { return VLC{Path: path}, nil },
+	"mpv":  func(path, _ string) (Player, error) { return MPV{Path: path}, nil },
+	"iina": func(path, _ string) (Player, error) { return IINA{Path: path}, nil },
+	"custom": func(_, command string) (Player, error) {
+		if strings.TrimSpace(command) == "" {
+			return nil, fmt.Errorf("özel oynatıcı için komut şablonu belirtilmedi (--player-cmd)")
+		}
+		return Command{Template: command}, nil
+	},
+}
+
+// New, adı verilen oynatıcıyı oluşturur.
+// path oynatıcının çalıştırılabilir dosyasının yolu, command ise "custom" oynatıcının komut şablonudur.
+func New(name, path, command string) (Player, error) {
+	factory, ok := factories[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("geçersiz oynatıcı: %s (kullanılabilir: %s)", name, strings.Join(Names(), ", "))
+	}
+	return factory(path, command)
+}
+
+// Names, kullanılabilir oynatıcı adlarını alfabetik sırayla döner.
+func Names() []string {
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// header, başlık adını büyük/küçük harf duyarsız arayarak değerini döner.
+func header(headers map[string]string, name string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// start, oynatıcı sürecini başlatır ve bir oturum döner.
+// Çalıştırılabilir dosya bulunamazsa anlaşılır bir hata döner.
+func start(playerName, binary string, args []string) (*Session, error) {
+	if _, err := exec.LookPath(binary); err != nil {
+		return nil, fmt.Errorf("%s sisteminizde yüklü değil veya belirtilen yolda bulunamadı", playerName)
+	}
+
+	cmd := exec.Command(binary, args...)
+	if err := cmd.Start(); err != nil {
+		return nil, err // Başlatma hatası
+	}
+	return newSession(cmd), nil
+}
@@ -1,36 +1,31 @@
 package player
 
 import (
-	"errors"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
-	"os/exec"
+	"net"
+	"net/http"
 	"runtime"
+	"strconv"
+	"time"
 )
 
-// VLCParams struct, VLC oynatıcı parametrelerini tutar.
-type VLCParams struct {
-	Url         string  // Oynatılacak video URL'si
-	SubtitleUrl *string // Altyazı URL'si (isteğe bağlı)
-	Title       string  // Video başlığı
-	VLCPath     string
+// VLC, VLC Media Player oynatıcısıdır.
+type VLC struct {
+	Path string // VLC'nin tam yolu (boşsa PATH'te aranır)
 }
 
-// isVLCInstalled fonksiyonu, sistemde VLC oynatıcısının yüklü olup olmadığını kontrol eder.
-func isVLCInstalled(vlcPath string) error {
-	vlcBinary := "vlc"
-	if vlcPath != "" {
-		vlcBinary = vlcPath
-	} else if runtime.GOOS == "windows" {
-		vlcBinary = "vlc.exe"
-	}
-	_, err := exec.LookPath(vlcBinary) // VLC'nin sistemde bulunup bulunmadığını kontrol eder
-	return err
+// Name, oynatıcının adını döner.
+func (v VLC) Name() string {
+	return "vlc"
 }
 
-// getVLCBinary platforma göre vlc binary adını döner
-func getVLCBinary(vlcPath string) string {
-	if vlcPath != "" {
-		return vlcPath
+// binary platforma göre vlc binary adını döner
+func (v VLC) binary() string {
+	if v.Path != "" {
+		return v.Path
 	}
 	if runtime.GOOS == "windows" {
 		return "vlc.exe"
@@ -38,33 +33,118 @@ func getVLCBinary(vlcPath string) string {
 	return "vlc"
 }
 
-// PlayVLC fonksiyonu, verilen parametrelerle VLC oynatıcıyı başlatır.
-func PlayVLC(params VLCParams) (*exec.Cmd, string, error) {
-	// VLC'nin yüklü olup olmadığını kontrol et
-	if err := isVLCInstalled(params.VLCPath); err != nil {
-		return nil, "", errors.New("VLC sisteminizde yüklü değil veya belirtilen yolda bulunamadı") // Yükleme hatası
-	}
-
+// Play, verilen parametrelerle VLC oynatıcıyı başlatır.
+// Oynatma konumu VLC'nin HTTP arayüzünden okunur; arayüz başlatılamazsa
+// oynatma yine de sürer, yalnızca konum bilinmez.
+func (v VLC) Play(params Params) (*Session, error) {
 	// VLC başlatma komutunu oluştur
 	args := []string{
-		"--fullscreen", // Tam ekran başlat
+		"--fullscreen",    // Tam ekran başlat
 		"--play-and-exit", // Oynatma bitince VLC'yi kapat
 		fmt.Sprintf("--meta-title=%s", params.Title), // Pencere başlığını ayarla
 	}
 
+	// VLC yalnızca Referer ve User-Agent başlıklarını destekler
+	if referer := header(params.Headers, "Referer"); referer != "" {
+		args = append(args, fmt.Sprintf("--http-referrer=%s", referer))
+	}
+	if userAgent := header(params.Headers, "User-Agent"); userAgent != "" {
+		args = append(args, fmt.Sprintf("--http-user-agent=%s", userAgent))
+	}
+
+	// Kaldığı yerden devam ediliyorsa başlangıç konumunu ekle
+	if params.StartTime > 0 {
+		args = append(args, fmt.Sprintf("--start-time=%d", int(params.StartTime)))
+	}
+
 	// Eğer altyazı URL'si varsa, altyazı dosyasını ekle
-	if params.SubtitleUrl != nil && *params.SubtitleUrl != "" {
-		args = append(args, fmt.Sprintf("--sub-file=%s", *params.SubtitleUrl))
+	if params.SubtitleURL != "" {
+		args = append(args, fmt.Sprintf("--sub-file=%s", params.SubtitleURL))
+	}
+
+	// Konumu okuyabilmek için HTTP arayüzünü yalnızca yerel adreste aç
+	port, portErr := freePort()
+	password := randomPassword()
+	if portErr == nil {
+		args = append(args,
+			"--extraintf=http",
+			"--http-host=127.0.0.1",
+			fmt.Sprintf("--http-port=%d", port),
+			fmt.Sprintf("--http-password=%s", password),
+		)
 	}
 
 	// Video URL'sini ekle
-	args = append(args, params.Url)
+	args = append(args, params.URL)
+
+	session, err := start("VLC", v.binary(), args)
+	if err != nil {
+		return nil, err
+	}
+	if portErr == nil {
+		go pollVLCStatus(session, port, password)
+	}
+	return session, nil
+}
+
+// vlcStatus, VLC HTTP arayüzünün status.json yanıtındaki gerekli alanlardır.
+type vlcStatus struct {
+	Time   float64 `json:"time"`
+	Length float64 `json:"length"`
+}
+
+// pollVLCStatus, VLC kapanana kadar oynatma konumunu saniyede bir okur.
+func pollVLCStatus(session *Session, port int, password string) {
+	client := &http.Client{Timeout: 2 * time.Second}
+	url := "http://127.0.0.1:" + strconv.Itoa(port) + "/requests/status.json"
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-session.done:
+			return
+		case <-ticker.C:
+		}
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return
+		}
+		req.SetBasicAuth("", password)
 
-	vlcBinary := getVLCBinary(params.VLCPath)
-	cmd := exec.Command(vlcBinary, args...)
-	if err := cmd.Start(); err != nil {
-		return cmd, "", err // Başlatma hatası
+		// VLC açılırken arayüz henüz hazır olmayabilir, hatalar yok sayılır
+		resp, err := client.Do(req)
+		if err != nil {
+			continue
+		}
+		var status vlcStatus
+		err = json.NewDecoder(resp.Body).Decode(&status)
+		resp.Body.Close()
+		if err != nil || status.Length <= 0 {
+			continue
+		}
+
+		session.update(PlaybackStatus{Position: status.Time, Duration: status.Length})
 	}
+}
 
-	return cmd, "", nil
-}
\ No newline at end of file
+// freePort, yerel adreste boş bir TCP portu bulur.
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// randomPassword, HTTP arayüzü için rastgele bir parola üretir.
+func randomPassword() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "anitr-cli"
+	}
+	return hex.EncodeToString(b)
+}
@@ -22,10 +22,10 @@ func ClientLogin() (bool, error) {
 func DiscordRPC(params internal.RPCParams, loggedIn bool) (bool, error) {
 	// Eğer Discord'a giriş yapılmamışsa, giriş yap
 	if !loggedIn {
-	    ok, err := ClientLogin()
-	   	if err != nil || !ok {
-	    	return false, fmt.Errorf("discord rpc login başarısız: %v", err)
-	    }
+		ok, err := ClientLogin()
+		if err != nil || !ok {
+			return false, fmt.Errorf("discord rpc login başarısız: %v", err)
+		}
 		loggedIn = true
 	}
 
@@ -40,6 +40,9 @@ func DiscordRPC(params internal.RPCParams, loggedIn bool) (bool, error) {
 	} else if params.EpisodeTitle != "" {
 		activityState = params.EpisodeTitle // Fallback to EpisodeTitle if no episode numbers
 	}
+	if params.Paused {
+		activityState = "⏸ " + activityState
+	}
 
 	err := client.SetActivity(client.Activity{
 		State:      activityState,     // Aktivite durumu
@@ -101,11 +104,11 @@ func DiscordRPC(params internal.RPCParams, loggedIn bool) (bool, error) {
 func RPCDetails(animeTitle, episodeTitle string, currentEpisode, totalEpisodes int, largeimg, largetext string) internal.RPCParams {
 	// RPC parametrelerini yapılandır
 	return internal.RPCParams{
-		AnimeTitle:    animeTitle,
-		EpisodeTitle:  episodeTitle,
+		AnimeTitle:     animeTitle,
+		EpisodeTitle:   episodeTitle,
 		CurrentEpisode: currentEpisode,
 		TotalEpisodes:  totalEpisodes,
-		LargeImage:    largeimg,
-		LargeText:     largetext,
+		LargeImage:     largeimg,
+		LargeText:      largetext,
 	}
 }
@@ -0,0 +1,8 @@
+// all paketi, tüm yerleşik anime kaynaklarını içe aktararak kayıt defterine eklenmelerini sağlar.
+// Yeni bir kaynak eklemek için paketini buraya boş içe aktarma (blank import) olarak eklemek yeterlidir.
+package all
+
+import (
+	_ "github.com/xeyossr/anitr-cli/internal/sources/animecix"
+	_ "github.com/xeyossr/anitr-cli/internal/sources/openanime"
+)
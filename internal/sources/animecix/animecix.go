@@ -1,6 +1,7 @@
 package animecix
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,22 +10,95 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/xeyossr/anitr-cli/internal"
+	"github.com/xeyossr/anitr-cli/internal/cache"
+	"github.com/xeyossr/anitr-cli/internal/httpclient"
+	"github.com/xeyossr/anitr-cli/internal/mirror"
 	"github.com/xeyossr/anitr-cli/internal/models"
+	"github.com/xeyossr/anitr-cli/internal/sources"
 	"github.com/xeyossr/anitr-cli/internal/utils"
 )
 
 type AnimeCix struct{}
 
+// AnimeCix kaynağını kayıt defterine ekle
+func init() {
+	sources.Register(sources.Entry{
+		Name:  "animecix",
+		Label: "AnimeciX",
+		Capabilities: sources.Capabilities{
+			Movies:   true,
+			Captions: true,
+		},
+		Source: AnimeCix{},
+	})
+}
+
 // AnimeCix API için yapılandırma ayarları
 var configAnimecix = internal.Config{
 	BaseUrl:        "https://animecix.tv/",
 	AlternativeUrl: "https://mangacix.net/",
-	VideoPlayers:   []string{"tau-video.xyz", "sibnet"},
+	VideoPlayers:   []string{"https://tau-video.xyz"},
 	HttpHeaders:    map[string]string{"Accept": "application/json", "User-Agent": "Mozilla/5.0"},
 }
 
+// client, AnimeCix ve video oynatıcısına yapılan isteklerde kullanılan HTTP istemcisidir
+var client = configAnimecix.NewClient(httpclient.Options{Timeout: 20 * time.Second, Retries: 2, RateLimit: 5})
+
+var (
+	// mirrors, AnimeCix API'sinin aynalarıdır
+	mirrors = configAnimecix.Mirrors("animecix")
+	// related, related-videos uç noktasının aynalarıdır; bu uç nokta önce alternatif adreste denenir
+	related = mirror.New("animecix-related", configAnimecix.AlternativeUrl, configAnimecix.BaseUrl)
+	// players, video oynatıcısı API'sinin aynalarıdır
+	players = configAnimecix.PlayerMirrors("animecix-player")
+)
+
+// getJson, API yolunu çalışan ilk aynadan ister ve JSON yanıtını döner
+func getJson(ctx context.Context, set *mirror.Set, path string) (interface{}, error) {
+	var data interface{}
+	err := set.Do(ctx, func(base string) error {
+		var err error
+		data, err = internal.GetJson(ctx, client, base+path)
+		return err
+	})
+	return data, err
+}
+
+// getCachedJson, getJson'ın önbellekli hâlidir; validate'ten geçen yanıt ttl süresi boyunca yeniden istenmez
+func getCachedJson(ctx context.Context, set *mirror.Set, path string, ttl time.Duration, validate cache.Validator) (interface{}, error) {
+	return cache.Remember(cache.Key("animecix", path), ttl, func() (interface{}, error) {
+		return getJson(ctx, set, path)
+	}, validate)
+}
+
+// fetchVideoResponse, video oynatıcısının API'sinden gömülü videonun akış adreslerini alır
+func fetchVideoResponse(ctx context.Context, embedID, vid string) (VideoResponse, error) {
+	var videoResp VideoResponse
+	err := players.Do(ctx, func(base string) error {
+		response, err := client.Get(ctx, fmt.Sprintf("%s/api/video/%s?vid=%s", base, embedID, vid))
+		if err != nil {
+			return fmt.Errorf("video verileri alınamadı: %w", err)
+		}
+		defer response.Body.Close()
+		if err := httpclient.CheckStatus(response); err != nil {
+			return err
+		}
+
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return fmt.Errorf("video verileri okunamadı: %w", err)
+		}
+		if err := json.Unmarshal(body, &videoResp); err != nil {
+			return fmt.Errorf("video verileri ayrıştırılamadı: %w", err)
+		}
+		return nil
+	})
+	return videoResp, err
+}
+
 // VideoURL, video URL'sinin etiket ve bağlantısını tutar
 type VideoURL struct {
 	Label string `json:"label"`
@@ -42,13 +116,13 @@ func (a AnimeCix) Source() string {
 }
 
 // GetSearchData, verilen sorguya göre anime verilerini döner
-func (a AnimeCix) GetSearchData(query string) ([]models.Anime, error) {
+func (a AnimeCix) GetSearchData(ctx context.Context, query string) ([]models.Anime, error) {
 	// Türkçe karakterleri ASCII'ye dönüştür ve boşlukları "-" ile değiştir
 	normalizedQuery := utils.NormalizeTurkishToASCII(query)
 	normalizedQuery = strings.ReplaceAll(normalizedQuery, " ", "-")
 
 	// Anime arama verilerini al
-	data, err := FetchAnimeSearchData(normalizedQuery)
+	data, err := FetchAnimeSearchData(ctx, normalizedQuery)
 	if err != nil {
 		return nil, err
 	}
@@ -96,9 +170,9 @@ func (a AnimeCix) GetSearchData(query string) ([]models.Anime, error) {
 }
 
 // GetSeasonsData, anime için sezon bilgilerini döner
-func (a AnimeCix) GetSeasonsData(params models.SeasonParams) ([]models.Season, error) {
+func (a AnimeCix) GetSeasonsData(ctx context.Context, params models.SeasonParams) ([]models.Season, error) {
 	// Sezon verilerini al
-	data, err := FetchAnimeSeasonsData(*params.Id)
+	data, err := FetchAnimeSeasonsData(ctx, *params.Id)
 	if err != nil {
 		return nil, err
 	}
@@ -112,64 +186,78 @@ func (a AnimeCix) GetSeasonsData(params models.SeasonParams) ([]models.Season, e
 }
 
 // GetEpisodesData, sezon için bölüm bilgilerini döner
-func (a AnimeCix) GetEpisodesData(params models.EpisodeParams) ([]models.Episode, error) {
+func (a AnimeCix) GetEpisodesData(ctx context.Context, params models.EpisodeParams) ([]models.Episode, error) {
 	// Bölüm verilerini al
-	episodesRaw, err := FetchAnimeEpisodesData(*params.SeasonID)
-	if err != nil {
+	// Bazı sezonlar alınamadıysa diğerlerinin bölümleri hatayla birlikte döner
+	episodesRaw, err := FetchAnimeEpisodesData(ctx, *params.SeasonID)
+	var partial *models.SeasonsError
+	if err != nil && !errors.As(err, &partial) {
 		return nil, fmt.Errorf("bölüm verileri alınamadı: %w", err)
 	}
 
 	var episodes []models.Episode
+	// Sezon içindeki sıra, altyazı verisini bulmak için gereklidir
+	seasonCounts := make(map[int]int)
 	// Bölümleri modele dönüştür
 	for i, item := range episodesRaw {
 		title, _ := item["name"].(string)
 		url, _ := item["url"].(string)
+		seasonNum, _ := internal.GetInt(item, "season_num")
+		episodeIndex := seasonCounts[seasonNum]
+		seasonCounts[seasonNum]++
+
+		episodeNum, ok := internal.GetInt(item, "episode_num")
+		if !ok {
+			episodeNum = episodeIndex + 1
+		}
+
 		episode := models.Episode{
-			ID:     url,
-			Title:  title,
-			Number: i + 1,
-			Extra:  map[string]interface{}{"season_num": item["season_num"]},
+			ID:             url,
+			Title:          title,
+			Season:         seasonNum,
+			Number:         episodeNum,
+			AbsoluteNumber: i + 1,
+			AirDate:        internal.GetTime(item, "created_at"),
+			Extra: map[string]interface{}{
+				"episode_index": episodeIndex,
+			},
 		}
 		episodes = append(episodes, episode)
 	}
 
-	return episodes, nil
+	return episodes, err
 }
 
-// GetWatchData, anime için izleme verilerini döner
-func (a AnimeCix) GetWatchData(req models.WatchParams) ([]models.Watch, error) {
+// GetWatchData, anime için video akışlarını döner
+func (a AnimeCix) GetWatchData(ctx context.Context, req models.WatchParams) ([]models.Stream, error) {
 	// Verilerin eksik olup olmadığını kontrol et
-	if req.IsMovie == nil || req.Url == nil || req.Id == nil || req.Extra == nil {
-		return nil, fmt.Errorf("panic")
+	if req.IsMovie == nil || req.Id == nil {
+		return nil, fmt.Errorf("film bilgisi veya anime ID'si eksik")
 	}
 
-	// Parametreleri al
-	var (
-		isMovie      bool                   = *req.IsMovie
-		url          string                 = *req.Url
-		id           int                    = *req.Id
-		Extra        map[string]interface{} = *req.Extra
-		seasonIndex  int                    = Extra["seasonIndex"].(int)
-		episodeIndex int                    = Extra["episodeIndex"].(int)
-	)
+	id := *req.Id
 
 	// Eğer filmse, film izleme verilerini al
-	if isMovie {
-		data, err := AnimeMovieWatchApiUrl(id)
+	if *req.IsMovie {
+		data, err := AnimeMovieWatchApiUrl(ctx, id)
 		if err != nil {
 			return nil, fmt.Errorf("film verileri alınamadı: %w", err)
 		}
 
 		// Video akışlarını kontrol et
-		streams, ok := data["video_streams"].([]interface{})
+		rawStreams, ok := data["video_streams"].([]interface{})
 		if !ok {
 			return nil, fmt.Errorf("video_streams verisi beklenen formatta değil")
 		}
 
-		var labels []string
-		var urls []string
+		var subtitles []models.Subtitle
+		if c, ok := data["caption_url"].(string); ok && c != "" {
+			subtitles = []models.Subtitle{{Language: "tr", URL: c}}
+		}
+
 		// Her bir video akışını listele
-		for _, s := range streams {
+		var streams []models.Stream
+		for _, s := range rawStreams {
 			item, ok := s.(map[string]interface{})
 			if !ok {
 				continue
@@ -177,61 +265,60 @@ func (a AnimeCix) GetWatchData(req models.WatchParams) ([]models.Watch, error) {
 			label, _ := item["label"].(string)
 			url, _ := item["url"].(string)
 
-			labels = append(labels, label)
-			urls = append(urls, url)
-		}
-
-		var captionUrl *string
-		if c, ok := data["caption_url"].(string); ok {
-			captionUrl = &c
+			streams = append(streams, models.Stream{
+				Quality:   label,
+				URL:       url,
+				Subtitles: subtitles,
+			})
 		}
 
-		// İzleme verilerini döndür
-		watch := models.Watch{
-			Labels:    labels,
-			Urls:      urls,
-			TRCaption: captionUrl,
-		}
+		models.SortStreams(streams)
+		return streams, nil
+	}
 
-		return []models.Watch{watch}, nil
+	if req.Episode == nil {
+		return nil, fmt.Errorf("bölüm bilgisi eksik")
 	}
+	episode := *req.Episode
 
 	// Bölüm izleme verilerini al
-	videoStreams, err := AnimeWatchApiUrl(url)
+	videoStreams, err := AnimeWatchApiUrl(ctx, episode.ID)
 	if err != nil {
 		return nil, fmt.Errorf("bölüm verileri alınamadı: %w", err)
 	}
 
 	// Altyazıyı al
-	captionUrl, err := FetchTRCaption(seasonIndex, episodeIndex, id)
-	if err != nil {
-		captionUrl = ""
+	episodeIndex, ok := episode.Extra["episode_index"].(int)
+	if !ok {
+		episodeIndex = episode.Number - 1
 	}
 
-	// Video akışlarını listele
-	var labels []string
-	var urls []string
-	for _, entry := range videoStreams {
-		labels = append(labels, entry["label"])
-		urls = append(urls, entry["url"])
+	var subtitles []models.Subtitle
+	captionUrl, err := FetchTRCaption(ctx, episode.Season-1, episodeIndex, id)
+	if err == nil && captionUrl != "" {
+		subtitles = []models.Subtitle{{Language: "tr", URL: captionUrl}}
 	}
 
-	// İzleme verisini döndür
-	watch := models.Watch{
-		Labels:    labels,
-		Urls:      urls,
-		TRCaption: &captionUrl,
+	// Video akışlarını listele
+	var streams []models.Stream
+	for _, entry := range videoStreams {
+		streams = append(streams, models.Stream{
+			Quality:   entry["label"],
+			URL:       entry["url"],
+			Subtitles: subtitles,
+		})
 	}
 
-	return []models.Watch{watch}, nil
+	models.SortStreams(streams)
+	return streams, nil
 }
 
 // FetchAnimeSearchData, anime arama verilerini alır
-func FetchAnimeSearchData(query string) ([]map[string]interface{}, error) {
+func FetchAnimeSearchData(ctx context.Context, query string) ([]map[string]interface{}, error) {
 	// Arama URL'sini oluştur
-	url := fmt.Sprintf("%ssecure/search/%s?type=&limit=20", configAnimecix.BaseUrl, query)
+	path := fmt.Sprintf("secure/search/%s?type=&limit=20", query)
 	// JSON verisini al
-	data, err := internal.GetJson(url, configAnimecix.HttpHeaders)
+	data, err := getCachedJson(ctx, mirrors, path, cache.SearchTTL, cache.HasFields("results"))
 
 	if err != nil {
 		return nil, err
@@ -276,9 +363,9 @@ func FetchAnimeSearchData(query string) ([]map[string]interface{}, error) {
 }
 
 // FetchAnimeSeasonsData, anime için sezon verilerini alır
-func FetchAnimeSeasonsData(id int) ([]int, error) {
-	url := fmt.Sprintf("%ssecure/related-videos?episode=1&season=1&titleId=%d&videoId=637113", configAnimecix.AlternativeUrl, id)
-	data, err := internal.GetJson(url, configAnimecix.HttpHeaders)
+func FetchAnimeSeasonsData(ctx context.Context, id int) ([]int, error) {
+	path := fmt.Sprintf("secure/related-videos?episode=1&season=1&titleId=%d&videoId=637113", id)
+	data, err := getCachedJson(ctx, related, path, cache.SeasonsTTL, cache.HasFields("videos"))
 	if err != nil {
 		return nil, fmt.Errorf("sezon verileri alınamadı: %w", err)
 	}
@@ -292,6 +379,9 @@ func FetchAnimeSeasonsData(id int) ([]int, error) {
 	if !ok {
 		return nil, fmt.Errorf("'videos' verisi yok veya beklenen formatta değil")
 	}
+	if len(videosField) == 0 {
+		return nil, fmt.Errorf("'videos' verisi boş")
+	}
 
 	video, ok := videosField[0].(map[string]interface{})
 	if !ok {
@@ -317,82 +407,116 @@ func FetchAnimeSeasonsData(id int) ([]int, error) {
 	return indices, nil
 }
 
-// FetchAnimeEpisodesData, anime için bölüm verilerini alır
-func FetchAnimeEpisodesData(id int) ([]map[string]interface{}, error) {
+// FetchAnimeEpisodesData, anime için bölüm verilerini alır. Sezonlar eşzamanlı istenir;
+// alınamayan sezonlar *models.SeasonsError ile bildirilir, diğerlerinin bölümleri yine döner.
+func FetchAnimeEpisodesData(ctx context.Context, id int) ([]map[string]interface{}, error) {
+	seasons, err := FetchAnimeSeasonsData(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("sezon verileri alınamadı: %w", err)
+	}
+
+	seasonNums := make([]int, len(seasons))
+	for i, seasonIndex := range seasons {
+		seasonNums[i] = seasonIndex + 1
+	}
+	all, err := sources.FetchSeasons(ctx, seasonNums, func(ctx context.Context, season int) ([]map[string]interface{}, error) {
+		return fetchSeasonEpisodes(ctx, id, season)
+	})
+
+	// Aynı bölüm birden fazla sezonda listelenirse yalnızca ilki kullanılır
 	var episodes []map[string]interface{}
 	seenEpisodes := make(map[string]bool)
-	seasons, err := FetchAnimeSeasonsData(id)
+	for _, episode := range all {
+		name := episode["name"].(string)
+		if !seenEpisodes[name] {
+			episodes = append(episodes, episode)
+			seenEpisodes[name] = true
+		}
+	}
+
+	return episodes, err
+}
 
+// fetchSeasonEpisodes, tek bir sezonun bölüm verilerini alır
+func fetchSeasonEpisodes(ctx context.Context, id, season int) ([]map[string]interface{}, error) {
+	path := fmt.Sprintf("secure/related-videos?episode=1&season=%d&titleId=%d&videoId=637113", season, id)
+	data, err := getCachedJson(ctx, related, path, cache.EpisodesTTL, cache.HasFields("videos"))
 	if err != nil {
-		return nil, fmt.Errorf("sezon verileri alınamadı: %w", err)
+		return nil, fmt.Errorf("bölüm verileri alınamadı: %w", err)
 	}
 
-	// Her sezon için bölüm verilerini al
-	for _, seasonIndex := range seasons {
-		url := fmt.Sprintf("%ssecure/related-videos?episode=1&season=%d&titleId=%d&videoId=637113", configAnimecix.AlternativeUrl, seasonIndex+1, id)
-		data, err := internal.GetJson(url, configAnimecix.HttpHeaders)
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("data beklenen formatta değil")
+	}
 
-		if err != nil {
-			return nil, fmt.Errorf("bölüm verileri alınamadı: %w", err)
-		}
+	videosRaw, ok := dataMap["videos"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("'videos' verisi yok veya beklenen formatta değil")
+	}
 
-		dataMap, ok := data.(map[string]interface{})
+	// Her bir video için bölüm verilerini ekle
+	var episodes []map[string]interface{}
+	for _, video := range videosRaw {
+		video, ok := video.(map[string]interface{})
 		if !ok {
-			return nil, fmt.Errorf("data beklenen formatta değil")
+			return nil, fmt.Errorf("video verisi beklenen formatta değil")
 		}
 
-		videosRaw, ok := dataMap["videos"].([]interface{})
+		name, ok := video["name"].(string)
 		if !ok {
-			return nil, fmt.Errorf("'videos' verisi yok veya beklenen formatta değil")
+			return nil, fmt.Errorf("name verisi beklenen formatta değil")
 		}
 
-		// Her bir video için bölüm verilerini ekle
-		for _, video := range videosRaw {
-			video, ok := video.(map[string]interface{})
-
-			if !ok {
-				return nil, err
-			}
-
-			name, ok := video["name"].(string)
-			if !ok {
-				return nil, fmt.Errorf("name verisi beklenen formatta değil")
-			}
-
-			if !seenEpisodes[name] {
-				episodeUrl, ok := video["url"].(string)
-
-				if !ok {
-					return nil, fmt.Errorf("url verisi beklenen formatta değil")
-				}
-
-				seasonNum := video["season_num"]
-				episode := map[string]interface{}{"name": name, "url": episodeUrl, "season_num": seasonNum}
-				episodes = append(episodes, episode)
-				seenEpisodes[name] = true
-			}
+		episodeUrl, ok := video["url"].(string)
+		if !ok {
+			return nil, fmt.Errorf("url verisi beklenen formatta değil")
 		}
+
+		// Sezon numarası gelmezse istenen sezon kullanılır
+		seasonNum, ok := internal.GetInt(video, "season_num")
+		if !ok {
+			seasonNum = season
+		}
+		episodes = append(episodes, map[string]interface{}{
+			"name":        name,
+			"url":         episodeUrl,
+			"season_num":  seasonNum,
+			"episode_num": video["episode_num"],
+			"created_at":  video["created_at"],
+		})
 	}
 
 	return episodes, nil
 }
 
 // AnimeWatchApiUrl, anime için izleme verilerini döner
-func AnimeWatchApiUrl(Url string) ([]map[string]string, error) {
-	watch_url := fmt.Sprintf("%s%s", configAnimecix.BaseUrl, Url)
-	resp, err := http.Get(watch_url)
+func AnimeWatchApiUrl(ctx context.Context, Url string) ([]map[string]string, error) {
+
+	// Bölüm sayfası video oynatıcısına yönlendirir; yönlendirilen adres alınır
+	var finalUrl string
+	err := mirrors.Do(ctx, func(base string) error {
+		resp, err := client.Get(ctx, base+Url)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		// 422 hatası alırsak, beklenen formatta veriler yok demektir
+		if resp.StatusCode == 422 {
+			return errors.New("bölüm verisi beklenen formatta değil")
+		}
+		if resp.StatusCode >= 500 {
+			return httpclient.CheckStatus(resp)
+		}
+		finalUrl = resp.Request.URL.String()
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	// 422 hatası alırsak, beklenen formatta veriler yok demektir
-	if resp.StatusCode == 422 {
-		return nil, errors.New("bölüm verisi beklenen formatta değil")
-	}
 
 	// Gelen URL'yi işle ve video verilerine ulaş
-	finalUrl := resp.Request.URL.String()
 	parsedUrl, err := url.Parse(finalUrl)
 	if err != nil {
 		return nil, err
@@ -408,23 +532,9 @@ func AnimeWatchApiUrl(Url string) ([]map[string]string, error) {
 	queryParams := parsedUrl.Query()
 	vid := queryParams.Get("vid")
 
-	apiUrl := fmt.Sprintf("https://%s/api/video/%s?vid=%s", configAnimecix.VideoPlayers[0], embedID, vid)
-	response, err := http.Get(apiUrl)
+	videoResp, err := fetchVideoResponse(ctx, embedID, vid)
 	if err != nil {
-		return nil, fmt.Errorf("video verileri alınamadı: %w", err)
-	}
-
-	defer response.Body.Close()
-
-	body, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, fmt.Errorf("video verileri okunamadı: %w", err)
-	}
-
-	var videoResp VideoResponse
-	err = json.Unmarshal(body, &videoResp)
-	if err != nil {
-		return nil, fmt.Errorf("video verileri ayrıştırılamadı: %w", err)
+		return nil, err
 	}
 
 	// Video URL'leri ve etiketlerini döndür
@@ -442,9 +552,9 @@ func AnimeWatchApiUrl(Url string) ([]map[string]string, error) {
 }
 
 // FetchTRCaption, Türkçe altyazıyı döner
-func FetchTRCaption(seasonIndex, episodeIndex, id int) (string, error) {
-	url := fmt.Sprintf("%ssecure/related-videos?episode=1&season=%d&titleId=%d&videoId=637113", configAnimecix.AlternativeUrl, seasonIndex+1, id)
-	data, err := internal.GetJson(url, configAnimecix.HttpHeaders)
+func FetchTRCaption(ctx context.Context, seasonIndex, episodeIndex, id int) (string, error) {
+	path := fmt.Sprintf("secure/related-videos?episode=1&season=%d&titleId=%d&videoId=637113", seasonIndex+1, id)
+	data, err := getCachedJson(ctx, related, path, cache.EpisodesTTL, cache.HasFields("videos"))
 	if err != nil {
 		return "", fmt.Errorf("altyazı verileri alınamadı: %w", err)
 	}
@@ -461,6 +571,9 @@ func FetchTRCaption(seasonIndex, episodeIndex, id int) (string, error) {
 	}
 
 	// İlgili bölümü al
+	if episodeIndex < 0 || episodeIndex >= len(videosSlice) {
+		return "", fmt.Errorf("bölüm sırası (%d) sezonun bölüm sayısının (%d) dışında", episodeIndex, len(videosSlice))
+	}
 	video, ok := videosSlice[episodeIndex].(map[string]interface{})
 	if !ok {
 		return "", fmt.Errorf("episode verisi yok veya beklenen formatta değil")
@@ -480,49 +593,62 @@ func FetchTRCaption(seasonIndex, episodeIndex, id int) (string, error) {
 
 		lang, ok := caption["language"].(string)
 		if ok && lang == "tr" {
-			return caption["url"].(string), nil
+			url, ok := caption["url"].(string)
+			if !ok {
+				return "", fmt.Errorf("altyazı adresi beklenen formatta değil")
+			}
+			return url, nil
 		}
 	}
 
-	// Eğer Türkçe altyazı bulunmazsa, bir hata döndür
+	// Eğer Türkçe altyazı bulunmazsa ilk altyazı kullanılır
 	if len(captions) == 0 {
 		return "", fmt.Errorf("altyazı bulunamadı")
 	}
-	caption0 := captions[0].(map[string]interface{})
-	return caption0["url"].(string), nil
+	caption0, ok := captions[0].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("caption verisi yok veya beklenen formatta değil")
+	}
+	url, ok := caption0["url"].(string)
+	if !ok {
+		return "", fmt.Errorf("altyazı adresi beklenen formatta değil")
+	}
+	return url, nil
 }
 
 // AnimeMovieWatchApiUrl, film için video URL'lerini döner
-func AnimeMovieWatchApiUrl(id int) (map[string]interface{}, error) {
-	Url := fmt.Sprintf("%ssecure/titles/%d?titleId=%d", configAnimecix.BaseUrl, id, id)
-
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", Url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Accept", configAnimecix.HttpHeaders["Accept"])
-	req.Header.Set("User-Agent", configAnimecix.HttpHeaders["User-Agent"])
-	req.Header.Set("x-e-h", "=.a")
+func AnimeMovieWatchApiUrl(ctx context.Context, id int) (map[string]interface{}, error) {
 
 	// API'den veri al
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("HTTP isteği başarısız: %w", err)
-	}
-	defer resp.Body.Close()
+	var result interface{}
+	err := mirrors.Do(ctx, func(base string) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%ssecure/titles/%d?titleId=%d", base, id, id), nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("x-e-h", "=.a")
 
-	// Gelen yanıtı çözümle
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("HTTP yanıtı okunamadı: %w", err)
-	}
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("HTTP isteği başarısız: %w", err)
+		}
+		defer resp.Body.Close()
+		if err := httpclient.CheckStatus(resp); err != nil {
+			return err
+		}
 
-	var result interface{}
-	err = json.Unmarshal(respBody, &result)
+		// Gelen yanıtı çözümle
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("HTTP yanıtı okunamadı: %w", err)
+		}
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			return fmt.Errorf("JSON ayrıştırma hatası: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("JSON ayrıştırma hatası: %w", err)
+		return nil, err
 	}
 
 	dataMap, ok := result.(map[string]interface{})
@@ -555,14 +681,10 @@ func AnimeMovieWatchApiUrl(id int) (map[string]interface{}, error) {
 		}
 
 		// Video URL'yi çözümle
-		client := &http.Client{}
-		req, err := http.NewRequest("GET", videoUrl, nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", videoUrl, nil)
 		if err != nil {
 			return nil, fmt.Errorf("HTTP isteği oluşturulamadı: %w", err)
 		}
-
-		req.Header.Set("Accept", configAnimecix.HttpHeaders["Accept"])
-		req.Header.Set("User-Agent", configAnimecix.HttpHeaders["User-Agent"])
 		req.Header.Set("x-e-h", "=.a")
 
 		resp, err := client.Do(req)
@@ -591,25 +713,9 @@ func AnimeMovieWatchApiUrl(id int) (map[string]interface{}, error) {
 		vid := queryParams.Get("vid")
 
 		// Video verilerini al
-		apiUrl := fmt.Sprintf("https://%s/api/video/%s?vid=%s", configAnimecix.VideoPlayers[0], embedID, vid)
-
-		response, err := http.Get(apiUrl)
-		if err != nil {
-			return nil, fmt.Errorf("video verileri alınamadı: %w", err)
-		}
-
-		defer response.Body.Close()
-
-		// JSON cevabını çözümle
-		respBody, err := io.ReadAll(response.Body)
-		if err != nil {
-			return nil, fmt.Errorf("video verileri okunamadı: %w", err)
-		}
-
-		var videoResp VideoResponse
-		err = json.Unmarshal(respBody, &videoResp)
+		videoResp, err := fetchVideoResponse(ctx, embedID, vid)
 		if err != nil {
-			return nil, fmt.Errorf("video verileri ayrıştırılamadı: %w", err)
+			return nil, err
 		}
 
 		// Video URL'lerini listele
@@ -1,16 +1,34 @@
 package openanime
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/xeyossr/anitr-cli/internal"
+	"github.com/xeyossr/anitr-cli/internal/cache"
+	"github.com/xeyossr/anitr-cli/internal/httpclient"
 	"github.com/xeyossr/anitr-cli/internal/models"
+	"github.com/xeyossr/anitr-cli/internal/sources"
 	"github.com/xeyossr/anitr-cli/internal/utils"
 )
 
 type OpenAnime struct{}
 
+// OpenAnime kaynağını kayıt defterine ekle
+func init() {
+	sources.Register(sources.Entry{
+		Name:  "openanime",
+		Label: "OpenAnime",
+		Capabilities: sources.Capabilities{
+			Fansubs: true,
+			Movies:  true,
+		},
+		Source: OpenAnime{},
+	})
+}
+
 // OpenAnime API için yapılandırma ayarları
 var configOpenAnime = internal.Config{
 	BaseUrl:      "https://api.openani.me",                                                                                                                                                                                                                          // API'nin temel URL'si
@@ -18,27 +36,60 @@ var configOpenAnime = internal.Config{
 	HttpHeaders:  map[string]string{"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/133.0.0.0 Safari/537.36", "Origin": "https://openani.me", "Referer": "https://openani.me", "Accept": "application/json"}, // HTTP başlıkları
 }
 
+// client, OpenAnime API isteklerinde kullanılan HTTP istemcisidir
+var client = configOpenAnime.NewClient(httpclient.Options{Timeout: 20 * time.Second, Retries: 2, RateLimit: 5})
+
+var (
+	// mirrors, OpenAnime API'sinin aynalarıdır
+	mirrors = configOpenAnime.Mirrors("openanime")
+	// players, video dosyalarının sunulduğu adreslerin aynalarıdır
+	players = configOpenAnime.PlayerMirrors("openanime-player")
+)
+
+// getJson, API yolunu çalışan ilk aynadan ister ve JSON yanıtını döner
+func getJson(ctx context.Context, path string) (interface{}, error) {
+	var data interface{}
+	err := mirrors.Do(ctx, func(base string) error {
+		var err error
+		data, err = internal.GetJson(ctx, client, base+path)
+		return err
+	})
+	return data, err
+}
+
+// getCachedJson, getJson'ın önbellekli hâlidir; validate'ten geçen yanıt ttl süresi boyunca yeniden istenmez
+func getCachedJson(ctx context.Context, path string, ttl time.Duration, validate cache.Validator) (interface{}, error) {
+	return cache.Remember(cache.Key("openanime", path), ttl, func() (interface{}, error) {
+		return getJson(ctx, path)
+	}, validate)
+}
+
 // Source, OpenAnime kaynağının adını döner
 func (o OpenAnime) Source() string {
 	return "openanime"
 }
 
 // GetSearchData, verilen sorguya göre anime verilerini döner
-func (o OpenAnime) GetSearchData(query string) ([]models.Anime, error) {
+func (o OpenAnime) GetSearchData(ctx context.Context, query string) ([]models.Anime, error) {
 	// Türkçe karakterleri ASCII'ye dönüştür ve boşlukları "+" ile değiştir
 	normalizedQuery := utils.NormalizeTurkishToASCII(query)
 	normalizedQuery = strings.ReplaceAll(normalizedQuery, " ", "+")
 
 	// Arama URL'sini oluştur ve JSON verisini al
-	url := fmt.Sprintf("%s/anime/search?q=%s", configOpenAnime.BaseUrl, normalizedQuery)
-	data, err := internal.GetJson(url, configOpenAnime.HttpHeaders)
+	path := fmt.Sprintf("/anime/search?q=%s", normalizedQuery)
+	data, err := getCachedJson(ctx, path, cache.SearchTTL, cache.IsList)
 	if err != nil {
 		return nil, fmt.Errorf("arama verileri alınamadı: %w", err)
 	}
 
+	items, ok := data.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("arama verisi beklenen formatta değil")
+	}
+
 	var returnData []models.Anime
 	// Alınan verileri anime modeline dönüştür
-	for _, item := range data.([]interface{}) {
+	for _, item := range items {
 		anime, ok := item.(map[string]interface{})
 		if !ok {
 			return nil, fmt.Errorf("geçersiz anime veri formatı")
@@ -55,9 +106,9 @@ func (o OpenAnime) GetSearchData(query string) ([]models.Anime, error) {
 			slug = ""
 		}
 
-		poster, ok := anime["pictures"].(map[string]interface{})["avatar"].(string)
-		if !ok {
-			poster = ""
+		var poster string
+		if pictures, ok := anime["pictures"].(map[string]interface{}); ok {
+			poster, _ = pictures["avatar"].(string)
 		}
 
 		// Anime bilgilerini döndür
@@ -73,10 +124,10 @@ func (o OpenAnime) GetSearchData(query string) ([]models.Anime, error) {
 }
 
 // GetSeasonsData, anime için sezon verilerini döner
-func (o OpenAnime) GetSeasonsData(params models.SeasonParams) ([]models.Season, error) {
+func (o OpenAnime) GetSeasonsData(ctx context.Context, params models.SeasonParams) ([]models.Season, error) {
 	// Sezon verilerini almak için URL'yi oluştur
-	url := fmt.Sprintf("%s/anime/%s", configOpenAnime.BaseUrl, *params.Slug)
-	data, err := internal.GetJson(url, configOpenAnime.HttpHeaders)
+	path := fmt.Sprintf("/anime/%s", *params.Slug)
+	data, err := getCachedJson(ctx, path, cache.SeasonsTTL, cache.HasFields("type"))
 	if err != nil {
 		return nil, fmt.Errorf("sezon verileri alınamadı: %w", err)
 	}
@@ -93,7 +144,10 @@ func (o OpenAnime) GetSeasonsData(params models.SeasonParams) ([]models.Season,
 		seasonCount = 1
 	}
 
-	contentType := seasonData["type"].(string)
+	contentType, ok := seasonData["type"].(string)
+	if !ok {
+		return nil, fmt.Errorf("'type' verisi yok veya beklenen formatta değil")
+	}
 	isMovie := strings.ToLower(contentType) == "movie"
 
 	// Sezon bilgilerini döndür
@@ -107,63 +161,83 @@ func (o OpenAnime) GetSeasonsData(params models.SeasonParams) ([]models.Season,
 }
 
 // GetEpisodesData, sezon için bölüm verilerini döner
-func (o OpenAnime) GetEpisodesData(params models.EpisodeParams) ([]models.Episode, error) {
+func (o OpenAnime) GetEpisodesData(ctx context.Context, params models.EpisodeParams) ([]models.Episode, error) {
 	// Sezon verilerini al
-	seasonData, err := o.GetSeasonsData(models.SeasonParams{Slug: params.Slug})
+	seasonData, err := o.GetSeasonsData(ctx, models.SeasonParams{Slug: params.Slug})
 	if err != nil {
 		return nil, fmt.Errorf("sezon bilgisi alınamadı: %w", err)
 	}
 
-	// Bölüm verilerini al
-	var episodes []models.Episode
 	seasondata := *seasonData[0].Seasons
 	seasonCount := int(seasondata[0])
+	seasons := make([]int, seasonCount)
+	for i := range seasons {
+		seasons[i] = i + 1
+	}
 
-	// Her bir sezon için bölüm verilerini al
-	for season := 1; season <= seasonCount; season++ {
-		url := fmt.Sprintf("%s/anime/%s/season/%d", configOpenAnime.BaseUrl, *params.Slug, season)
-		data, err := internal.GetJson(url, configOpenAnime.HttpHeaders)
-		if err != nil {
-			return nil, fmt.Errorf("sezon %d için bölüm verileri alınamadı: %w", season, err)
-		}
+	// Sezonların bölüm verilerini eşzamanlı al; alınamayan sezonlar hatayla bildirilir
+	episodes, err := sources.FetchSeasons(ctx, seasons, func(ctx context.Context, season int) ([]models.Episode, error) {
+		return fetchSeasonEpisodes(ctx, *params.Slug, season)
+	})
+	for i := range episodes {
+		episodes[i].AbsoluteNumber = i + 1
+	}
+
+	return episodes, err
+}
 
-		seasonInfo, ok := data.(map[string]interface{})["season"].(map[string]interface{})
+// fetchSeasonEpisodes, tek bir sezonun bölüm verilerini alır
+func fetchSeasonEpisodes(ctx context.Context, slug string, season int) ([]models.Episode, error) {
+	path := fmt.Sprintf("/anime/%s/season/%d", slug, season)
+	data, err := getCachedJson(ctx, path, cache.EpisodesTTL, cache.HasFields("season"))
+	if err != nil {
+		return nil, fmt.Errorf("bölüm verileri alınamadı: %w", err)
+	}
+
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("sezon verisi beklenen formatta değil")
+	}
+
+	seasonInfo, ok := dataMap["season"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("'season' verisi yok veya beklenen formatta değil")
+	}
+
+	// Bölüm verilerini işleyip listele
+	episodesRaw, ok := seasonInfo["episodes"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("'episodes' verisi yok veya beklenen formatta değil")
+	}
+
+	// Her bir bölümü ekle
+	var episodes []models.Episode
+	for _, episodeRaw := range episodesRaw {
+		episode, ok := episodeRaw.(map[string]interface{})
 		if !ok {
 			continue
 		}
 
-		// Bölüm verilerini işleyip listele
-		episodesRaw, ok := seasonInfo["episodes"].([]interface{})
+		episodeNumber, _ := internal.GetInt(episode, "episodeNumber")
+		seasonNumber, ok := internal.GetInt(seasonInfo, "season_number")
 		if !ok {
-			continue
+			seasonNumber = season
 		}
+		name := fmt.Sprintf("%d. Sezon, %d. Bölüm", seasonNumber, episodeNumber)
 
-		// Her bir bölümü ekle
-		for _, episodeRaw := range episodesRaw {
-			episode, ok := episodeRaw.(map[string]interface{})
-			if !ok {
-				continue
-			}
-
-			episodeNumber, _ := episode["episodeNumber"].(float64)
-			seasonNumber, _ := seasonInfo["season_number"].(float64)
-			name := fmt.Sprintf("%d. Sezon, %d. Bölüm", int(seasonNumber), int(episodeNumber))
-
-			episodes = append(episodes, models.Episode{
-				Title:  name,
-				Number: int(episodeNumber),
-				Extra: map[string]interface{}{
-					"season_num": seasonNumber,
-				},
-			})
-		}
+		episodes = append(episodes, models.Episode{
+			Title:   name,
+			Season:  seasonNumber,
+			Number:  episodeNumber,
+			AirDate: internal.GetTime(episode, "airDate", "air_date", "createdAt"),
+		})
 	}
 
 	return episodes, nil
 }
 
 // GetFansubsData, fansub verilerini döner
-func (o OpenAnime) GetFansubsData(params models.FansubParams) ([]models.Fansub, error) {
+func (o OpenAnime) GetFansubsData(ctx context.Context, params models.FansubParams) ([]models.Fansub, error) {
 	// Gereksiz boş parametrelerin kontrolü
 	if params.Slug == nil || params.SeasonNum == nil || params.EpisodeNum == nil {
 		return nil, fmt.Errorf("slug, sezon numarası veya bölüm numarası eksik")
@@ -175,14 +249,18 @@ func (o OpenAnime) GetFansubsData(params models.FansubParams) ([]models.Fansub,
 	episodeNum := *params.EpisodeNum
 
 	// Fansub verilerini almak için URL'yi oluştur
-	url := fmt.Sprintf("%s/anime/%s/season/%d/episode/%d", configOpenAnime.BaseUrl, slug, seasonNum, episodeNum)
-	data, err := internal.GetJson(url, configOpenAnime.HttpHeaders)
+	path := fmt.Sprintf("/anime/%s/season/%d/episode/%d", slug, seasonNum, episodeNum)
+	data, err := getJson(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("fansub verileri alınamadı: %w", err)
 	}
 
 	// Raw fansub verilerini al
-	rawFansubs, ok := data.(map[string]interface{})["fansubs"].([]interface{})
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("fansub verisi beklenen formatta değil")
+	}
+	rawFansubs, ok := dataMap["fansubs"].([]interface{})
 	if !ok {
 		return nil, fmt.Errorf("fansubs verisi eksik veya hatalı")
 	}
@@ -226,43 +304,57 @@ func (o OpenAnime) GetFansubsData(params models.FansubParams) ([]models.Fansub,
 	return fansubs, nil
 }
 
-// GetWatchData, izleme verilerini döner
-func (o OpenAnime) GetWatchData(req models.WatchParams) ([]models.Watch, error) {
+// GetWatchData, bölümün seçilen fansub'a ait video akışlarını döner
+func (o OpenAnime) GetWatchData(ctx context.Context, req models.WatchParams) ([]models.Stream, error) {
 	// Eksik parametre kontrolü
-	if req.Slug == nil || req.Extra == nil {
-		return nil, fmt.Errorf("slug veya ekstra bilgiler eksik")
+	if req.Slug == nil {
+		return nil, fmt.Errorf("slug eksik")
 	}
 
 	slug := *req.Slug
-	extra := *req.Extra
 
-	// Sezon ve bölüm numarasını al
-	seasonNum, ok := extra["season_num"].(int)
-	if !ok {
-		return nil, fmt.Errorf("season_num geçersiz veya eksik")
+	// Sezon ve bölüm numarasını al (film için bölüm verilmeyebilir)
+	seasonNum, episodeNum := 1, 0
+	if req.Episode != nil {
+		if req.Episode.Season > 0 {
+			seasonNum = req.Episode.Season
+		}
+		episodeNum = req.Episode.Number
 	}
 
-	episodeNum, ok := extra["episode_num"].(int)
-	if !ok {
-		return nil, fmt.Errorf("episode_num geçersiz veya eksik")
+	// Fansub verilerini al ve tercih edilen fansub'u seç
+	fansubs, err := o.GetFansubsData(ctx, models.FansubParams{
+		Slug:       &slug,
+		SeasonNum:  &seasonNum,
+		EpisodeNum: &episodeNum,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// İzleme URL'sini oluştur
-	baseURL := fmt.Sprintf("%s/anime/%s/season/%d/episode/%d", configOpenAnime.BaseUrl, slug, int(seasonNum), int(episodeNum))
-
-	// Fansub verilerini al
-	fansubs := extra["fansubs"].([]models.Fansub)
-	selectedFansubId := extra["selected_fansub_id"].(int)
+	fansub := fansubs[0]
+	if req.FansubID != nil {
+		for _, f := range fansubs {
+			if *f.ID == *req.FansubID {
+				fansub = f
+				break
+			}
+		}
+	}
 
 	// Video URL'sini oluştur
-	videoURL := fmt.Sprintf("%s?fansub=%s", baseURL, *fansubs[selectedFansubId].ID)
-	data, err := internal.GetJson(videoURL, configOpenAnime.HttpHeaders)
+	path := fmt.Sprintf("/anime/%s/season/%d/episode/%d?fansub=%s", slug, seasonNum, episodeNum, *fansub.ID)
+	data, err := getJson(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("video bağlantıları alınamadı: %w", err)
 	}
 
 	// Bölüm verilerini al
-	episodeData, ok := data.(map[string]interface{})["episodeData"].(map[string]interface{})
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("video verisi beklenen formatta değil")
+	}
+	episodeData, ok := dataMap["episodeData"].(map[string]interface{})
 	if !ok {
 		return nil, fmt.Errorf("episodeData eksik veya hatalı")
 	}
@@ -273,8 +365,16 @@ func (o OpenAnime) GetWatchData(req models.WatchParams) ([]models.Watch, error)
 		return nil, fmt.Errorf("video dosyaları bulunamadı veya geçersiz")
 	}
 
-	var labels []string
-	var urls []string
+	// Video sunucusu tarayıcıdan geliyormuş gibi istek bekler
+	headers := map[string]string{
+		"User-Agent": configOpenAnime.HttpHeaders["User-Agent"],
+		"Referer":    configOpenAnime.HttpHeaders["Referer"],
+	}
+
+	// Adresler oynatıcıya verildiği için ayakta olan video sunucusu önceden seçilir
+	playerBase := players.Healthy(ctx)
+
+	var streams []models.Stream
 
 	// Her bir video dosyasını işleyip listele
 	for _, f := range files {
@@ -285,7 +385,6 @@ func (o OpenAnime) GetWatchData(req models.WatchParams) ([]models.Watch, error)
 
 		// Dosya URL'sini ve çözünürlüğünü al
 		urlRaw, urlOK := fileData["file"].(string)
-		url := fmt.Sprintf("%s/animes/%s/%d/%s", configOpenAnime.VideoPlayers[0], slug, seasonNum, urlRaw)
 		resolutionVal, resOK := fileData["resolution"].(float64)
 
 		// URL veya çözünürlük eksikse devam et
@@ -294,20 +393,18 @@ func (o OpenAnime) GetWatchData(req models.WatchParams) ([]models.Watch, error)
 		}
 
 		// Çözünürlük etiketini ve URL'yi listeye ekle
-		labels = append(labels, fmt.Sprintf("%dp", int(resolutionVal)))
-		urls = append(urls, url)
+		streams = append(streams, models.Stream{
+			Quality: fmt.Sprintf("%dp", int(resolutionVal)),
+			URL:     fmt.Sprintf("%s/animes/%s/%d/%s", playerBase, slug, seasonNum, urlRaw),
+			Headers: headers,
+		})
 	}
 
 	// Eğer geçerli URL yoksa hata döndür
-	if len(urls) == 0 {
+	if len(streams) == 0 {
 		return nil, fmt.Errorf("geçerli video bağlantısı bulunamadı")
 	}
 
-	// İzleme verilerini döndür
-	return []models.Watch{
-		{
-			Labels: labels,
-			Urls:   urls,
-		},
-	}, nil
+	models.SortStreams(streams)
+	return streams, nil
 }
@@ -0,0 +1,96 @@
+// sources paketi, anime kaynaklarının kaydedildiği ve bulunduğu kayıt defterini içerir.
+package sources
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xeyossr/anitr-cli/internal/models"
+)
+
+// Capabilities, bir kaynağın desteklediği özellikleri tanımlar.
+type Capabilities struct {
+	Fansubs  bool // Fansub seçimi destekleniyor mu (kaynak models.FansubSource uygular)
+	Movies   bool // Film içerikleri destekleniyor mu
+	Captions bool // Ayrı altyazı dosyası sağlanıyor mu
+}
+
+// Entry, kayıt defterindeki tek bir kaynağı temsil eder.
+type Entry struct {
+	Name         string             // Kaynağın benzersiz adı (models.AnimeSource.Source() ile aynı)
+	Label        string             // Menülerde gösterilecek ad
+	Capabilities Capabilities       // Kaynağın desteklediği özellikler
+	Source       models.AnimeSource // Kaynağın kendisi
+}
+
+var (
+	entries []Entry
+	byName  = make(map[string]int)
+)
+
+// Register, bir kaynağı kayıt defterine ekler. Kaynak paketlerinin init fonksiyonundan çağrılır.
+// Aynı adla ikinci kez kayıt yapılırsa panic oluşur.
+func Register(e Entry) {
+	name := strings.ToLower(e.Name)
+	if name == "" || e.Source == nil {
+		panic("sources: kaynak adı ve kaynak boş olamaz")
+	}
+	if _, dup := byName[name]; dup {
+		panic(fmt.Sprintf("sources: %q kaynağı zaten kayıtlı", name))
+	}
+	if e.Label == "" {
+		e.Label = e.Name
+	}
+	e.Name = name
+	byName[name] = len(entries)
+	entries = append(entries, e)
+}
+
+// List, kayıtlı tüm kaynakları kayıt sırasıyla döner.
+func List() []Entry {
+	return append([]Entry(nil), entries...)
+}
+
+// Labels, kayıtlı kaynakların menü adlarını kayıt sırasıyla döner.
+func Labels() []string {
+	labels := make([]string, 0, len(entries))
+	for _, e := range entries {
+		labels = append(labels, e.Label)
+	}
+	return labels
+}
+
+// Get, verilen ada sahip kaynağı döner. Büyük/küçük harf duyarsızdır.
+func Get(name string) (Entry, bool) {
+	i, ok := byName[strings.ToLower(name)]
+	if !ok {
+		return Entry{}, false
+	}
+	return entries[i], true
+}
+
+// ByLabel, verilen menü adına sahip kaynağı döner.
+func ByLabel(label string) (Entry, bool) {
+	for _, e := range entries {
+		if e.Label == label {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Lookup, kaynağı önce adına sonra menü adına göre arar.
+// Bulunamazsa kayıtlı kaynakların listesini içeren bir hata döner.
+func Lookup(nameOrLabel string) (Entry, error) {
+	if e, ok := Get(nameOrLabel); ok {
+		return e, nil
+	}
+	if e, ok := ByLabel(nameOrLabel); ok {
+		return e, nil
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name)
+	}
+	return Entry{}, fmt.Errorf("geçersiz kaynak: %s (kullanılabilir: %s)", nameOrLabel, strings.Join(names, ", "))
+}
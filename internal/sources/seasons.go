@@ -0,0 +1,62 @@
+package sources
+
+import (
+	"context"
+	"sync"
+
+	"github.com/xeyossr/anitr-cli/internal/models"
+)
+
+// SeasonConcurrency, bölüm listesi alınırken aynı anda istenebilecek en fazla sezon sayısıdır.
+const SeasonConcurrency = 4
+
+// FetchSeasons, fetch'i verilen sezonlar için en fazla SeasonConcurrency eşzamanlı istekle
+// çağırır. Sonuçlar, isteklerin bitiş sırasından bağımsız olarak sezonların verildiği sırayla
+// birleştirilir. Alınamayan sezonlar atlanır ve diğer sezonların sonuçlarıyla birlikte
+// *models.SeasonsError olarak bildirilir. Bağlam iptal edilirse kısmi sonuç yerine
+// bağlamın hatası döner.
+func FetchSeasons[T any](ctx context.Context, seasons []int, fetch func(ctx context.Context, season int) ([]T, error)) ([]T, error) {
+	results := make([][]T, len(seasons))
+	errs := make([]error, len(seasons))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, SeasonConcurrency)
+	for i, season := range seasons {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i], errs[i] = fetch(ctx, season)
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var (
+		all    []T
+		failed map[int]error
+	)
+	for i, season := range seasons {
+		if errs[i] != nil {
+			if failed == nil {
+				failed = make(map[int]error)
+			}
+			failed[season] = errs[i]
+			continue
+		}
+		all = append(all, results[i]...)
+	}
+	if failed != nil {
+		return all, &models.SeasonsError{Failed: failed}
+	}
+	return all, nil
+}
@@ -0,0 +1,119 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/xeyossr/anitr-cli/internal/models"
+)
+
+func TestFetchSeasons(t *testing.T) {
+	errNotFound := errors.New("404")
+
+	tests := []struct {
+		name       string
+		seasons    []int
+		fail       map[int]bool
+		want       []string
+		wantFailed []int
+	}{
+		{name: "sezon yok", seasons: nil},
+		{name: "tek sezon", seasons: []int{1}, want: []string{"1-1", "1-2"}},
+		{
+			name:    "sıra korunur",
+			seasons: []int{1, 2, 3, 4, 5, 6, 7, 8},
+			want:    []string{"1-1", "1-2", "2-1", "2-2", "3-1", "3-2", "4-1", "4-2", "5-1", "5-2", "6-1", "6-2", "7-1", "7-2", "8-1", "8-2"},
+		},
+		{name: "verilen sıra", seasons: []int{3, 1, 2}, want: []string{"3-1", "3-2", "1-1", "1-2", "2-1", "2-2"}},
+		{
+			name:       "kısmi hata",
+			seasons:    []int{1, 2, 3, 4},
+			fail:       map[int]bool{2: true, 4: true},
+			want:       []string{"1-1", "1-2", "3-1", "3-2"},
+			wantFailed: []int{2, 4},
+		},
+		{name: "tüm sezonlar başarısız", seasons: []int{1, 2}, fail: map[int]bool{1: true, 2: true}, wantFailed: []int{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Önce verilen sezonlar en geç biter; sonuçlar yine de verilen sırayla birleşmeli
+			fetch := func(ctx context.Context, season int) ([]string, error) {
+				time.Sleep(time.Duration(10-season) * time.Millisecond)
+				if tt.fail[season] {
+					return nil, errNotFound
+				}
+				return []string{fmt.Sprintf("%d-1", season), fmt.Sprintf("%d-2", season)}, nil
+			}
+
+			got, err := FetchSeasons(context.Background(), tt.seasons, fetch)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("FetchSeasons = %v, beklenen %v", got, tt.want)
+			}
+			if tt.wantFailed == nil {
+				if err != nil {
+					t.Fatalf("beklenmeyen hata: %v", err)
+				}
+				return
+			}
+			var partial *models.SeasonsError
+			if !errors.As(err, &partial) {
+				t.Fatalf("hata *models.SeasonsError değil: %v", err)
+			}
+			if !reflect.DeepEqual(partial.Seasons(), tt.wantFailed) {
+				t.Fatalf("başarısız sezonlar %v, beklenen %v", partial.Seasons(), tt.wantFailed)
+			}
+			for _, season := range tt.wantFailed {
+				if !errors.Is(partial.Failed[season], errNotFound) {
+					t.Fatalf("sezon %d hatası %v", season, partial.Failed[season])
+				}
+			}
+		})
+	}
+}
+
+func TestFetchSeasonsConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	seasons := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	_, err := FetchSeasons(context.Background(), seasons, func(ctx context.Context, season int) ([]int, error) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		return []int{season}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := peak.Load(); p > SeasonConcurrency || p < 2 {
+		t.Fatalf("aynı anda %d istek çalıştı, sınır %d", p, SeasonConcurrency)
+	}
+}
+
+func TestFetchSeasonsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	got, err := FetchSeasons(ctx, []int{1, 2, 3, 4, 5, 6, 7, 8}, func(ctx context.Context, season int) ([]int, error) {
+		calls.Add(1)
+		if season == 1 {
+			cancel()
+		}
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) || got != nil {
+		t.Fatalf("iptal edilen bağlamda FetchSeasons = %v, %v", got, err)
+	}
+	if n := calls.Load(); n > SeasonConcurrency {
+		t.Fatalf("iptalden sonra yeni istek başlatıldı: %d çağrı", n)
+	}
+}
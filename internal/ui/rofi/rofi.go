@@ -93,3 +93,34 @@ func InputFromUser(params internal.UiParams) (string, error) {
 	resp := strings.TrimSpace(string(out))
 	return resp, nil
 }
+
+// Countdown, rofi'de "Oynat" / "İptal" seçeneklerini gösterir. Süre dolduğunda
+// rofi'nin -timeout-delay seçeneği ilk öğeyi (Oynat) kendiliğinden seçer.
+// Kullanıcı iptal eder veya pencereyi kapatırsa false döner.
+func Countdown(params internal.UiParams, seconds int) (bool, error) {
+	// "rofi"nin yüklü olup olmadığını kontrol et
+	err := isRofiExist()
+	if err != nil {
+		return false, errors.New("rofi modunun çalışması için rofi'nin sisteminize yüklü olması gerekmektedir")
+	}
+
+	mesg := fmt.Sprintf("%s (%d saniye içinde başlıyor)", params.Label, seconds)
+	args := []string{"-dmenu", "-p", "anitr-cli", "-mesg", mesg,
+		"-timeout-delay", fmt.Sprint(seconds), "-timeout-action", "kb-accept-entry"}
+
+	// Eğer rofi özel bayrakları varsa, onları argümanlara ekle
+	if params.RofiFlags != nil {
+		flags := strings.Split(*params.RofiFlags, " ")
+		args = append(args, flags...)
+	}
+
+	cmd := exec.Command("rofi", args...)
+	cmd.Stdin = bytes.NewBufferString("Oynat\nİptal\n")
+
+	// Esc ile kapatılan rofi sıfır dışı çıkış kodu döner, bu iptal sayılır
+	out, err := cmd.Output()
+	if err != nil {
+		return false, nil
+	}
+	return strings.TrimSpace(string(out)) == "Oynat", nil
+}
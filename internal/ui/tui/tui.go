@@ -1,13 +1,16 @@
 package tui
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"strings"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -18,14 +21,14 @@ import (
 
 // Renkler ve stil ayarları
 var (
-	highlightFgColor = "#33ccbb" 
-	normalFgColor    = "#f0f0f0" 
-	highlightColor   = "#33ccbb" 
-	filterInputFg    = "#ff007f" 
-	filterCursorFg   = "#ff007f" 
-	inputPromptFg    = "#33ccbb" 
-	inputTextFg      = "#f0f0f0" 
-	inputCursorFg    = "#ff007f" 
+	highlightFgColor = "#33ccbb"
+	normalFgColor    = "#f0f0f0"
+	highlightColor   = "#33ccbb"
+	filterInputFg    = "#ff007f"
+	filterCursorFg   = "#ff007f"
+	inputPromptFg    = "#33ccbb"
+	inputTextFg      = "#f0f0f0"
+	inputCursorFg    = "#ff007f"
 	selectionMark    = "▸ "
 
 	pinkHighlight = lipgloss.NewStyle().Foreground(lipgloss.Color(highlightColor))
@@ -44,6 +47,47 @@ var (
 			Padding(0, 2)
 )
 
+// Theme, TUI renklerini tutar. Boş bırakılan renkler değiştirilmez.
+type Theme struct {
+	Highlight   string // Seçili öğe rengi
+	Normal      string // Normal öğe rengi
+	Filter      string // Arama kutusu rengi
+	InputPrompt string // Giriş istemi rengi
+	InputText   string // Giriş metni rengi
+	Cursor      string // İmleç rengi
+}
+
+// SetTheme, TUI renklerini değiştirir ve stilleri yeniden oluşturur
+func SetTheme(t Theme) {
+	setColor(&highlightFgColor, t.Highlight)
+	setColor(&highlightColor, t.Highlight)
+	setColor(&normalFgColor, t.Normal)
+	setColor(&filterInputFg, t.Filter)
+	setColor(&inputPromptFg, t.InputPrompt)
+	setColor(&inputTextFg, t.InputText)
+	setColor(&filterCursorFg, t.Cursor)
+	setColor(&inputCursorFg, t.Cursor)
+
+	pinkHighlight = lipgloss.NewStyle().Foreground(lipgloss.Color(highlightColor))
+	filterInputStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(filterInputFg)).
+		Bold(true)
+	highlightStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(highlightFgColor)).
+		Bold(true).
+		Padding(0, 2)
+	normalStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(normalFgColor)).
+		Padding(0, 2)
+}
+
+// setColor, değer boş değilse rengi değiştirir
+func setColor(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
+
 // listItem, list elemanlarının türüdür
 type listItem struct {
 	title    string
@@ -120,13 +164,13 @@ func (d slimDelegate) Render(w io.Writer, m list.Model, index int, item list.Ite
 
 // SelectionListModel, seçim listesini tutan modeldir
 type SelectionListModel struct {
-	list         list.Model
-	quitting     bool
-	selected     []string
-	selectedMap  map[string]struct{}
-	multiSelect  bool // Add this field
-	err          error
-	width        int
+	list        list.Model
+	quitting    bool
+	selected    []string
+	selectedMap map[string]struct{}
+	multiSelect bool // Add this field
+	err         error
+	width       int
 }
 
 // NewSelectionListModel, yeni bir SelectionListModel oluşturur
@@ -324,4 +368,166 @@ func InputFromUser(params internal.UiParams) (string, error) {
 	}
 
 	return model.textInput.Value(), nil
-}
\ No newline at end of file
+}
+
+// countdownTickMsg, geri sayımın her saniyesinde gönderilen mesajdır
+type countdownTickMsg struct{}
+
+// countdownTick, bir saniye sonra countdownTickMsg gönderir
+func countdownTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return countdownTickMsg{} })
+}
+
+// CountdownModel, iptal edilebilir geri sayımı tutan modeldir
+type CountdownModel struct {
+	label     string
+	remaining int
+	accepted  bool
+	quitting  bool
+}
+
+// Init, geri sayımı başlatır
+func (m CountdownModel) Init() tea.Cmd {
+	return countdownTick()
+}
+
+// Update, geri sayımı ve tuş girişlerini işler
+func (m CountdownModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case countdownTickMsg:
+		m.remaining--
+		if m.remaining <= 0 {
+			m.accepted = true
+			m.quitting = true
+			return m, tea.Quit
+		}
+		return m, countdownTick()
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "enter":
+			m.accepted = true
+			m.quitting = true
+			return m, tea.Quit
+		case "ctrl+c", "esc", "q":
+			m.quitting = true
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+// View, geri sayımın görünümünü döndürür
+func (m CountdownModel) View() string {
+	if m.quitting {
+		return ""
+	}
+	text := fmt.Sprintf("%s\n\n%d saniye içinde başlıyor...\n\n", m.label, m.remaining)
+	help := lipgloss.NewStyle().Faint(true).Render("enter: hemen başlat • esc/q: iptal")
+	return lipgloss.NewStyle().Padding(1, 2).Render(pinkHighlight.Bold(true).Render(text) + help)
+}
+
+// Countdown, verilen süre boyunca iptal edilebilir bir geri sayım gösterir.
+// Süre dolarsa veya Enter'a basılırsa true, kullanıcı iptal ederse false döner.
+func Countdown(params internal.UiParams, seconds int) (bool, error) {
+	p := tea.NewProgram(CountdownModel{label: params.Label, remaining: seconds}, tea.WithAltScreen())
+	m, err := p.Run()
+	if err != nil {
+		if params.Logger != nil {
+			params.Logger.LogError(fmt.Errorf("bubbletea p.Run() error in Countdown: %w", err))
+		}
+		return false, err
+	}
+	return m.(CountdownModel).accepted, nil
+}
+
+// spinnerDoneMsg, arka plandaki iş bittiğinde gönderilen mesajdır
+type spinnerDoneMsg struct{}
+
+// SpinnerModel, arka planda süren bir işin bitmesini beklerken dönen bir gösterge gösterir
+type SpinnerModel struct {
+	spinner    spinner.Model
+	label      string
+	cancel     context.CancelFunc
+	cancelling bool
+	err        error
+	quitting   bool
+}
+
+// Init, göstergeyi başlatır
+func (m SpinnerModel) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+// Update, işin bitişini ve tuş girişlerini işler. İptal edilen iş bitene kadar gösterge açık kalır.
+func (m SpinnerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case spinnerDoneMsg:
+		m.quitting = true
+		return m, tea.Quit
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			m.err = utils.ErrCancelled
+		case "ctrl+c":
+			m.err = utils.ErrQuit
+		default:
+			return m, nil
+		}
+		m.cancelling = true
+		m.cancel()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(msg)
+	return m, cmd
+}
+
+// View, göstergenin görünümünü döndürür
+func (m SpinnerModel) View() string {
+	if m.quitting {
+		return ""
+	}
+	text := m.label
+	if m.cancelling {
+		text = "İptal ediliyor..."
+	}
+	help := lipgloss.NewStyle().Faint(true).Render("esc: iptal")
+	return lipgloss.NewStyle().Padding(1, 2).Render(m.spinner.View() + " " + pinkHighlight.Render(text) + "\n\n" + help)
+}
+
+// Spinner, fn çalışırken dönen bir gösterge gösterir ve fn'in hatasını döner.
+// Esc ile fn'in bağlamı iptal edilir ve utils.ErrCancelled, Ctrl+C ile utils.ErrQuit döner.
+// fn bitmeden dönülmez; böylece fn, çağıranın verilerine dönüşten sonra dokunmaz.
+func Spinner(ctx context.Context, params internal.UiParams, fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	p := tea.NewProgram(SpinnerModel{
+		spinner: spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(pinkHighlight)),
+		label:   params.Label,
+		cancel:  cancel,
+	}, tea.WithAltScreen())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+		p.Send(spinnerDoneMsg{})
+	}()
+
+	m, err := p.Run()
+	cancel()
+	fnErr := <-done
+	if err != nil {
+		if params.Logger != nil {
+			params.Logger.LogError(fmt.Errorf("bubbletea p.Run() error in Spinner: %w", err))
+		}
+		return err
+	}
+	if model := m.(SpinnerModel); model.err != nil {
+		return model.err
+	}
+	return fnErr
+}
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -52,3 +53,30 @@ func InputFromUser(params internal.UiParams) (string, error) {
 	}
 	return response, nil
 }
+
+// Verilen süre boyunca iptal edilebilir bir geri sayım gösterir
+// Süre dolarsa veya kullanıcı onaylarsa true döner
+func Countdown(params internal.UiParams, seconds int) (bool, error) {
+	if params.Mode == "rofi" {
+		ok, err := rofi.Countdown(params, seconds)
+		if err != nil {
+			return false, fmt.Errorf("rofi geri sayımı gösterilemedi: %w", err)
+		}
+		return ok, nil
+	}
+
+	ok, err := tui.Countdown(params, seconds)
+	if err != nil {
+		return false, fmt.Errorf("tui geri sayımı gösterilemedi: %w", err)
+	}
+	return ok, nil
+}
+
+// Uzun süren bir işlem (ör. ağ isteği) sürerken yükleniyor göstergesi gösterir
+// tui'de Esc ile işlem iptal edilebilir; rofi'de işlem göstergesiz çalışır
+func Spinner(ctx context.Context, params internal.UiParams, fn func(ctx context.Context) error) error {
+	if params.Mode == "rofi" {
+		return fn(ctx)
+	}
+	return tui.Spinner(ctx, params, fn)
+}
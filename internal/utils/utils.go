@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -10,11 +11,18 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
+
+	"github.com/xeyossr/anitr-cli/internal/httpclient"
+	"github.com/xeyossr/anitr-cli/internal/paths"
 )
 
 // Kullanıcının çıkış talebini temsil eden özel bir hata.
 var ErrQuit = errors.New("quit requested")
 
+// Kullanıcının süren bir işlemi (Esc ile) iptal ettiğini temsil eden hata.
+var ErrCancelled = errors.New("işlem iptal edildi")
+
 // Logger, hata ve mesajları bir dosyaya yazmak için yapılandırılmış bir log yapısıdır.
 type Logger struct {
 	File *os.File    // Log dosyasının kendisi
@@ -38,12 +46,15 @@ func getTempDir() string {
 	return "/tmp"
 }
 
-// GetImage, verilen URL'den bir görsel indirir ve geçici bir dosyaya kaydeder.
+// GetImage, verilen URL'den bir görsel indirir ve önbellek dizinine kaydeder.
 func GetImage(url string) (string, error) {
-	tempDir := getTempDir()
-	tempPath := filepath.Join(tempDir, "poster.png")
+	cacheDir, err := paths.Ensure(paths.CacheDir())
+	if err != nil {
+		cacheDir = getTempDir()
+	}
+	tempPath := filepath.Join(cacheDir, "poster.png")
 
-	resp, err := http.Get(url)
+	resp, err := httpclient.Default.Get(context.Background(), url)
 	if err != nil {
 		return "", fmt.Errorf("görsel indirilemedi: %w", err)
 	}
@@ -67,10 +78,14 @@ func GetImage(url string) (string, error) {
 	return tempPath, nil
 }
 
-// NewLogger, işletim sistemine göre uygun dizinde bir log dosyası oluşturur ve Logger döner.
+// NewLogger, durum dizininde (Linux'ta ~/.local/state/anitr-cli) bir log dosyası oluşturur ve Logger döner.
+// Durum dizini oluşturulamazsa geçici dizin kullanılır.
 func NewLogger() (*Logger, error) {
-	tempDir := getTempDir()
-	logPath := filepath.Join(tempDir, "anitr-cli.log")
+	logDir, err := paths.Ensure(paths.StateDir())
+	if err != nil {
+		logDir = getTempDir()
+	}
+	logPath := filepath.Join(logDir, "anitr-cli.log")
 
 	file, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
@@ -136,13 +151,10 @@ func CheckErr(err error, logger *Logger) bool {
 
 // IsValidImage, verilen URL'nin geçerli bir görsel olup olmadığını kontrol eder.
 func IsValidImage(url string) bool {
-	client := &http.Client{}
-	req, err := http.NewRequest("HEAD", url, nil)
-	if err != nil {
-		return false
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	resp, err := client.Do(req)
+	resp, err := httpclient.Default.Head(ctx, url)
 	if err != nil {
 		return false
 	}
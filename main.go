@@ -1,189 +1,139 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"runtime"
 	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/xeyossr/anitr-cli/internal"
+	"github.com/xeyossr/anitr-cli/internal/cache"
+	"github.com/xeyossr/anitr-cli/internal/config"
 	"github.com/xeyossr/anitr-cli/internal/downloader"
 	"github.com/xeyossr/anitr-cli/internal/flags"
+	"github.com/xeyossr/anitr-cli/internal/history" // Import the new history package
+	"github.com/xeyossr/anitr-cli/internal/httpclient"
 	"github.com/xeyossr/anitr-cli/internal/models"
+	"github.com/xeyossr/anitr-cli/internal/naming"
+	"github.com/xeyossr/anitr-cli/internal/paths"
 	"github.com/xeyossr/anitr-cli/internal/player"
 	"github.com/xeyossr/anitr-cli/internal/rpc"
-	"github.com/xeyossr/anitr-cli/internal/sources/animecix"
-	"github.com/xeyossr/anitr-cli/internal/sources/openanime"
+	"github.com/xeyossr/anitr-cli/internal/sources"
+	_ "github.com/xeyossr/anitr-cli/internal/sources/all"
 	"github.com/xeyossr/anitr-cli/internal/ui"
+	"github.com/xeyossr/anitr-cli/internal/ui/tui"
 	"github.com/xeyossr/anitr-cli/internal/utils"
-	"github.com/xeyossr/anitr-cli/internal/history" // Import the new history package
 )
 
-func updateWatchAPI(
-	source string,
-	episodeData []models.Episode,
-	index, id, seasonIndex, selectedFansubIndex int,
+// fetchStreams, seçilen bölüm (veya film) için kaynaktan video akışlarını alır.
+// Akışlar çözünürlüğe göre yüksekten düşüğe sıralı döner.
+func fetchStreams(
+	ctx context.Context,
+	source models.AnimeSource,
+	episodes []models.Episode,
+	index, id int,
+	slug string,
 	isMovie bool,
-	slug *string,
-) (map[string]interface{}, []models.Fansub, error) {
-	var (
-		captionData []map[string]string
-		fansubData  []models.Fansub
-		captionURL  string
-		err         error
-	)
-
-	switch source {
-	case "animecix":
-		if isMovie {
-			data, err := animecix.AnimeMovieWatchApiUrl(id)
-			if err != nil {
-				return nil, nil, fmt.Errorf("animecix movie API çağrısı başarısız: %w", err)
-			}
-			captionURLIface := data["caption_url"]
-			captionURL, _ = captionURLIface.(string)
-			streamsIface, ok := data["video_streams"]
-			if !ok {
-				return nil, nil, fmt.Errorf("video_streams beklenen formatta değil")
-			}
-			rawStreams, _ := streamsIface.([]interface{})
-			for _, streamIface := range rawStreams {
-				stream, _ := streamIface.(map[string]interface{})
-				label := internal.GetString(stream, "label")
-				url := internal.GetString(stream, "url")
-				captionData = append(captionData, map[string]string{"label": label, "url": url})
-			}
-		} else {
-			if index < 0 || index >= len(episodeData) {
-				return nil, nil, fmt.Errorf("index out of range")
-			}
-			urlData := episodeData[index].ID
-			captionData, err = animecix.AnimeWatchApiUrl(urlData)
-			if err != nil {
-				return nil, nil, fmt.Errorf("animecix watch API çağrısı başarısız: %w", err)
-			}
-			seasonEpisodeIndex := 0
-			for i := 0; i < index; i++ {
-				if sn, ok := episodeData[i].Extra["season_num"].(int); ok {
-					if sn-1 == seasonIndex {
-						seasonEpisodeIndex++
-					}
-				} else if snf, ok := episodeData[i].Extra["season_num"].(float64); ok {
-					if int(snf)-1 == seasonIndex {
-						seasonEpisodeIndex++
-					}
-				}
-			}
-			captionURL, err = animecix.FetchTRCaption(seasonIndex, seasonEpisodeIndex, id)
-			if err != nil {
-				captionURL = ""
-			}
-		}
+	fansubID string,
+) ([]models.Stream, error) {
+	if index < 0 || index >= len(episodes) {
+		return nil, fmt.Errorf("index out of range")
+	}
 
-	case "openanime":
-		if slug == nil {
-			return nil, nil, fmt.Errorf("slug gerekli")
-		}
-		if index < 0 || index >= len(episodeData) {
-			return nil, nil, fmt.Errorf("index out of range")
-		}
-		ep := episodeData[index]
-		seasonNum := 0
-		episodeNum := 0
-
-		if sn, ok := ep.Extra["season_num"].(int); ok {
-			seasonNum = sn
-		} else if snf, ok := ep.Extra["season_num"].(float64); ok {
-			seasonNum = int(snf)
-		} else {
-			return nil, nil, fmt.Errorf("season_num beklenen formatta değil")
-		}
-		if en, ok := ep.Extra["episode_num"].(int); ok {
-			episodeNum = en
-		} else if enf, ok := ep.Extra["episode_num"].(float64); ok {
-			episodeNum = int(enf)
-		} else {
-			episodeNum = ep.Number
-		}
+	params := models.WatchParams{
+		Slug:    &slug,
+		Id:      &id,
+		IsMovie: &isMovie,
+		Episode: &episodes[index],
+	}
+	if fansubID != "" {
+		params.FansubID = &fansubID
+	}
 
-		fansubParams := models.FansubParams{
-			Slug:       slug,
-			SeasonNum:  &seasonNum,
-			EpisodeNum: &episodeNum,
-		}
-		fansubData, err = openanime.OpenAnime{}.GetFansubsData(fansubParams)
-		if err != nil {
-			return nil, nil, fmt.Errorf("fansub data API çağrısı başarısız: %w", err)
-		}
-		if selectedFansubIndex < 0 || selectedFansubIndex >= len(fansubData) {
-			return nil, nil, fmt.Errorf("seçilen fansub indeksi geçersiz")
-		}
+	streams, err := source.GetWatchData(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("%s izleme verisi alınamadı: %w", source.Source(), err)
+	}
+	if len(streams) == 0 {
+		return nil, fmt.Errorf("%s izleme verisi boş", source.Source())
+	}
 
-		watchParams := models.WatchParams{
-			Slug:    slug,
-			Id:      &id,
-			IsMovie: &isMovie,
-			Extra: &map[string]interface{}{
-				"season_num":         seasonNum,
-				"episode_num":        episodeNum,
-				"fansubs":            fansubData,
-				"selected_fansub_id": selectedFansubIndex,
-			},
-		}
-		watches, err := openanime.OpenAnime{}.GetWatchData(watchParams)
-		if err != nil {
-			return nil, nil, fmt.Errorf("openanime watch data alınamadı: %w", err)
-		}
-		if len(watches) < 1 {
-			return nil, nil, fmt.Errorf("openanime watch data boş")
-		}
-		w := watches[0]
-		captionData = make([]map[string]string, len(w.Labels))
-		for i := range w.Labels {
-			captionData[i] = map[string]string{
-				"label": w.Labels[i],
-				"url":   w.Urls[i],
-			}
-		}
-		if w.TRCaption != nil {
-			captionURL = *w.TRCaption
-		}
+	models.SortStreams(streams)
+	return streams, nil
+}
 
-	default:
-		return nil, nil, fmt.Errorf("geçersiz kaynak: %s", source)
+// fetchFansubs, fansub destekleyen kaynaklarda seçilen bölümün fansub listesini alır.
+func fetchFansubs(ctx context.Context, source models.AnimeSource, episode models.Episode, id int, slug string) ([]models.Fansub, error) {
+	fansubSource, ok := source.(models.FansubSource)
+	if !sourceCapabilities(source).Fansubs || !ok {
+		return nil, fmt.Errorf("%s kaynağı fansub seçimini desteklemiyor", source.Source())
 	}
 
-	sort.Slice(captionData, func(i, j int) bool {
-		labelI := strings.TrimRight(captionData[i]["label"], "p")
-		labelJ := strings.TrimRight(captionData[j]["label"], "p")
-		intI, _ := strconv.Atoi(labelI)
-		intJ, _ := strconv.Atoi(labelJ)
-		return intI > intJ
+	seasonNum := max(episode.Season, 1)
+	episodeNum := episode.Number
+
+	fansubs, err := fansubSource.GetFansubsData(ctx, models.FansubParams{
+		Slug:       &slug,
+		Id:         &id,
+		SeasonNum:  &seasonNum,
+		EpisodeNum: &episodeNum,
 	})
+	if err != nil {
+		return nil, fmt.Errorf("fansub verisi alınamadı: %w", err)
+	}
+	return fansubs, nil
+}
+
+// resolveFansubID, adı veya ID'si verilen fansub'ın ID'sini bölümün fansub listesinden bulur.
+// Ad karşılaştırması büyük/küçük harf duyarsızdır.
+func resolveFansubID(ctx context.Context, source models.AnimeSource, episode models.Episode, id int, slug string, nameOrID string) (string, error) {
+	fansubs, err := fetchFansubs(ctx, source, episode, id, slug)
+	if err != nil {
+		return "", err
+	}
 
-	labels := []string{}
-	urls := []string{}
-	for _, item := range captionData {
-		labels = append(labels, item["label"])
-		urls = append(urls, item["url"])
+	var names []string
+	for _, f := range fansubs {
+		if f.ID == nil || f.Name == nil {
+			continue
+		}
+		if *f.ID == nameOrID || strings.EqualFold(*f.Name, nameOrID) {
+			return *f.ID, nil
+		}
+		names = append(names, *f.Name)
 	}
+	return "", fmt.Errorf("fansub bulunamadı: %s (mevcut: %s)", nameOrID, strings.Join(names, ", "))
+}
 
-	return map[string]interface{}{
-		"labels":      labels,
-		"urls":        urls,
-		"caption_url": captionURL,
-	}, fansubData, nil
+// sourceCapabilities, kaynağın kayıt defterinde bildirdiği özellikleri döner.
+func sourceCapabilities(source models.AnimeSource) sources.Capabilities {
+	entry, _ := sources.Get(source.Source())
+	return entry.Capabilities
+}
+
+// playerSubtitle, kaynak ayrı altyazı dosyası sağlıyorsa oynatıcıya verilecek altyazının
+// adresini döner.
+func playerSubtitle(source models.AnimeSource, stream models.Stream) string {
+	if !sourceCapabilities(source).Captions {
+		return ""
+	}
+	return stream.SubtitleURL()
 }
 
 func selectSource(uiMode string, rofiFlags string, logger *utils.Logger) (string, models.AnimeSource) {
 	for {
-		sourceList := []string{"OpenAnime", "AnimeciX"}
+		sourceList := sources.Labels()
 
 		appCtx := App{uiMode: &uiMode, rofiFlags: &rofiFlags, logger: logger}
 		selectedSourceSlice, err := showSelection(appCtx, sourceList, "Kaynak seç ", "generic", nil)
@@ -194,27 +144,36 @@ func selectSource(uiMode string, rofiFlags string, logger *utils.Logger) (string
 		selectedSource := selectedSourceSlice[0]
 		utils.FailIfErr(err, logger)
 
-		var source models.AnimeSource
-		switch strings.ToLower(selectedSource) {
-		case "openanime":
-			source = openanime.OpenAnime{}
-		case "animecix":
-			source = animecix.AnimeCix{}
-		default:
+		entry, ok := sources.ByLabel(selectedSource)
+		if !ok {
 			fmt.Printf("\033[31m[!] Geçersiz kaynak seçimi: %s\033[0m\n", selectedSource)
 			time.Sleep(1500 * time.Millisecond)
 			continue
 		}
-		return selectedSource, source
+		return entry.Label, entry.Source
 	}
 }
 
-func searchAnime(source models.AnimeSource, uiMode string, rofiFlags string, logger *utils.Logger) ([]models.Anime, []string, []string, map[string]models.Anime) {
+// searchAnime, kullanıcıdan arama sorgusu alır ve kaynakta arar. Arama Esc ile iptal
+// edilirse sorgu yeniden istenir; oturum iptal edilirse bağlamın hatası döner.
+func searchAnime(cfx App) ([]models.Anime, []string, []string, map[string]models.Anime, error) {
+	source, logger := *cfx.source, cfx.logger
 	for {
-		query, err := ui.InputFromUser(internal.UiParams{Mode: uiMode, RofiFlags: &rofiFlags, Label: "Anime ara ", Logger: logger})
+		query, err := ui.InputFromUser(internal.UiParams{Mode: *cfx.uiMode, RofiFlags: cfx.rofiFlags, Label: "Anime ara ", Logger: logger})
 		utils.FailIfErr(err, logger)
 
-		searchData, err := source.GetSearchData(query)
+		var searchData []models.Anime
+		err = loading(cfx, "Aranıyor...", func(ctx context.Context) error {
+			var err error
+			searchData, err = source.GetSearchData(ctx, query)
+			return err
+		})
+		if cfx.interrupted() {
+			return nil, nil, nil, nil, cfx.ctx.Err()
+		}
+		if errors.Is(err, utils.ErrCancelled) {
+			continue
+		}
 		utils.FailIfErr(err, logger)
 
 		if searchData == nil {
@@ -223,9 +182,9 @@ func searchAnime(source models.AnimeSource, uiMode string, rofiFlags string, log
 			continue
 		}
 
-	animeNames := make([]string, 0, len(searchData))
-	animeTypes := make([]string, 0, len(searchData))
-	animeMap := make(map[string]models.Anime)
+		animeNames := make([]string, 0, len(searchData))
+		animeTypes := make([]string, 0, len(searchData))
+		animeMap := make(map[string]models.Anime)
 
 		for _, item := range searchData {
 			animeNames = append(animeNames, item.Title)
@@ -241,7 +200,7 @@ func searchAnime(source models.AnimeSource, uiMode string, rofiFlags string, log
 			}
 		}
 
-		return searchData, animeNames, animeTypes, animeMap
+		return searchData, animeNames, animeTypes, animeMap, nil
 	}
 }
 
@@ -274,61 +233,73 @@ func selectAnime(animeNames []string, searchData []models.Anime, uiMode string,
 	}
 }
 
-func getAnimeIDs(source models.AnimeSource, selectedAnime models.Anime) (int, string) {
+func getAnimeIDs(selectedAnime models.Anime) (int, string) {
 	var selectedAnimeID int
 	var selectedAnimeSlug string
 
-	if source.Source() == "animecix" && selectedAnime.ID != nil {
+	if selectedAnime.ID != nil {
 		selectedAnimeID = *selectedAnime.ID
-	} else if source.Source() == "openanime" && selectedAnime.Slug != nil {
+	}
+	if selectedAnime.Slug != nil {
 		selectedAnimeSlug = *selectedAnime.Slug
 	}
 	return selectedAnimeID, selectedAnimeSlug
 }
 
-func getEpisodesAndNames(source models.AnimeSource, isMovie bool, selectedAnimeID int, selectedAnimeSlug string, selectedAnimeName string, logger *utils.Logger) ([]models.Episode, []string, bool, int, error) {
+func getEpisodesAndNames(ctx context.Context, source models.AnimeSource, isMovie bool, selectedAnime models.Anime, logger *utils.Logger) ([]models.Episode, []string, bool, error) {
 	var (
-		episodes            []models.Episode
-		episodeNames        []string
-		selectedSeasonIndex int
-		err                 error
+		episodes     []models.Episode
+		episodeNames []string
+		err          error
 	)
 
-	if source.Source() == "openanime" {
-		seasonData, err := source.GetSeasonsData(models.SeasonParams{Slug: &selectedAnimeSlug})
+	selectedAnimeID, selectedAnimeSlug := getAnimeIDs(selectedAnime)
+
+	// Arama sonucu tür bilgisi taşımıyorsa filmi sezon verisinden tespit et
+	if sourceCapabilities(source).Movies && selectedAnime.TitleType == nil {
+		seasonData, err := source.GetSeasonsData(ctx, models.SeasonParams{Id: &selectedAnimeID, Slug: &selectedAnimeSlug})
 		if err != nil {
 			logger.LogError(err)
-			return nil, nil, false, 0, fmt.Errorf("sezon verisi alınamadı: %w", err)
+			return nil, nil, false, fmt.Errorf("sezon verisi alınamadı: %w", err)
+		}
+		if len(seasonData) > 0 && seasonData[0].IsMovie != nil {
+			isMovie = *seasonData[0].IsMovie
 		}
-		isMovie = *seasonData[0].IsMovie
 	}
 
 	if !isMovie {
-		episodes, err = source.GetEpisodesData(models.EpisodeParams{SeasonID: &selectedAnimeID, Slug: &selectedAnimeSlug})
-		if err != nil {
-			return nil, nil, false, 0, fmt.Errorf("bölüm verisi alınamadı: %w", err)
+		episodes, err = source.GetEpisodesData(ctx, models.EpisodeParams{SeasonID: &selectedAnimeID, Slug: &selectedAnimeSlug})
+		var partial *models.SeasonsError
+		if errors.As(err, &partial) && len(episodes) > 0 {
+			// Alınabilen sezonlarla devam et, eksik sezonları bildir
+			logger.LogError(err)
+			var failed []string
+			for _, season := range partial.Seasons() {
+				failed = append(failed, strconv.Itoa(season))
+			}
+			fmt.Fprintf(os.Stderr, "[!] Bazı sezonların bölümleri alınamadı (sezon %s), diğer sezonlarla devam ediliyor.\n", strings.Join(failed, ", "))
+		} else if err != nil {
+			return nil, nil, false, fmt.Errorf("bölüm verisi alınamadı: %w", err)
 		}
 
 		if len(episodes) == 0 {
-			return nil, nil, false, 0, fmt.Errorf("hiçbir bölüm bulunamadı")
+			return nil, nil, false, fmt.Errorf("hiçbir bölüm bulunamadı")
 		}
 
 		episodeNames = make([]string, 0, len(episodes))
 		for _, e := range episodes {
 			episodeNames = append(episodeNames, e.Title)
 		}
-
-		selectedSeasonIndex = int(episodes[0].Extra["season_num"].(float64)) - 1
 	} else {
-		episodeNames = []string{selectedAnimeName}
+		episodeNames = []string{selectedAnime.Title}
 		episodes = []models.Episode{{
-			Title: selectedAnimeName,
-			Extra: map[string]interface{}{"season_num": float64(1)},
+			Title:          selectedAnime.Title,
+			Season:         1,
+			AbsoluteNumber: 1,
 		}}
-		selectedSeasonIndex = 0
 	}
 
-	return episodes, episodeNames, isMovie, selectedSeasonIndex, nil
+	return episodes, episodeNames, isMovie, nil
 }
 
 func playAnimeLoop(
@@ -341,19 +312,26 @@ func playAnimeLoop(
 	selectedAnimeSlug string,
 	selectedAnimeName string,
 	isMovie bool,
-	selectedSeasonIndex int,
 	uiMode string,
 	rofiFlags string,
 	posterURL string,
 	disableRPC bool,
 	logger *utils.Logger,
 	myAnimeListURL string,
+	start watchState,
 ) (models.AnimeSource, string, bool) {
 
 	selectedEpisodeIndex := 0
-	selectedFansubIdx := 0
-	selectedResolution := ""
-	selectedResolutionIdx := 0
+	if start.episodeIndex > 0 && start.episodeIndex < len(episodes) {
+		selectedEpisodeIndex = start.episodeIndex
+	}
+	selectedFansubID := start.fansubID
+	fansubLabel := start.fansubID // Dosya adlarında kullanılan fansub adı (bilinmiyorsa ID)
+	selectedResolution := start.resolution
+	autoPlay := start.autoPlay
+
+	// Otomatik oynatmada bir sonraki bölümün akışları oynatma sırasında önceden alınır
+	var preload *streamPreload
 
 	loggedIn, err := rpc.ClientLogin()
 	if err != nil || !loggedIn {
@@ -361,6 +339,10 @@ func playAnimeLoop(
 	}
 
 	for {
+		if cfx.interrupted() {
+			return source, selectedSource, false
+		}
+
 		watchMenu := []string{}
 		if !isMovie {
 			watchMenu = append(watchMenu, "İzle", "Sonraki bölüm", "Önceki bölüm", "Bölüm seç", "Çözünürlük seç", "İndir")
@@ -368,24 +350,36 @@ func playAnimeLoop(
 			watchMenu = append(watchMenu, "İzle", "Çözünürlük seç", "İndir")
 		}
 
-		if strings.ToLower(selectedSource) == "openanime" {
+		if sourceCapabilities(source).Fansubs {
 			watchMenu = append(watchMenu, "Fansub seç")
 		}
 
+		if !isMovie {
+			watchMenu = append(watchMenu, bingeLabel(*cfx.binge))
+		}
+
 		watchMenu = append(watchMenu, "Geri", "Anime ara", "Çık")
 
-		appCtx := App{uiMode: &uiMode, rofiFlags: &rofiFlags, logger: logger}
-		optionSlice, err := showSelection(appCtx, watchMenu, selectedAnimeName, "", nil)
-		utils.FailIfErr(err, logger)
+		// Devam etme akışında menü gösterilmeden seçili bölüm bir kez oynatılır
+		option := "İzle"
+		if autoPlay {
+			autoPlay = false
+		} else {
+			appCtx := App{uiMode: &uiMode, rofiFlags: &rofiFlags, logger: logger, history: cfx.history, ctx: cfx.ctx}
+			optionSlice, err := showSelection(appCtx, watchMenu, selectedAnimeName, "", nil)
+			utils.FailIfErr(err, logger)
 
-		if len(optionSlice) == 0 {
-			return source, selectedSource, true
+			if len(optionSlice) == 0 {
+				return source, selectedSource, true
+			}
+			option = optionSlice[0]
 		}
-		option := optionSlice[0]
 
 		switch option {
 		case "Geri":
 			return source, selectedSource, true
+		case bingeLabel(true), bingeLabel(false):
+			*cfx.binge = !*cfx.binge
 		case "İzle", "Sonraki bölüm", "Önceki bölüm":
 			if option == "Sonraki bölüm" {
 				if selectedEpisodeIndex+1 >= len(episodes) {
@@ -401,89 +395,115 @@ func playAnimeLoop(
 				selectedEpisodeIndex--
 			}
 
-			selectedSeasonIndex = int(episodes[selectedEpisodeIndex].Extra["season_num"].(float64)) - 1
-
-			data, _, err := updateWatchAPI(
-				strings.ToLower(selectedSource),
-				episodes,
-				selectedEpisodeIndex,
-				selectedAnimeID,
-				selectedSeasonIndex,
-				selectedFansubIdx,
-				isMovie,
-				&selectedAnimeSlug,
-			)
+			var streams []models.Stream
+			if preloaded, ok := preload.result(selectedEpisodeIndex, selectedFansubID); ok {
+				streams, err = preloaded.streams, preloaded.err
+			} else {
+				streams, err = loadStreams(cfx, source, episodes, selectedEpisodeIndex, selectedAnimeID, selectedAnimeSlug, isMovie, selectedFansubID)
+			}
+			preload = nil
+			if errors.Is(err, utils.ErrCancelled) || cfx.interrupted() {
+				continue
+			}
 			if err != nil {
 				fmt.Printf("[!] Bölüm oynatılamadı: %s\n", err)
 				time.Sleep(1500 * time.Millisecond)
 				continue
 			}
 
-			labels := data["labels"].([]string)
-			urls := data["urls"].([]string)
-			subtitle := data["caption_url"].(string)
-
+			// Seçili çözünürlük yoksa en yüksek çözünürlük kullanılır
+			stream, _ := models.SelectStream(streams, selectedResolution)
 			if selectedResolution == "" {
-				selectedResolutionIdx = 0
-				if len(labels) > 0 {
-					selectedResolution = labels[selectedResolutionIdx]
-				}
-			}
-			if selectedResolutionIdx >= len(urls) {
-				selectedResolutionIdx = len(urls) - 1
+				selectedResolution = stream.Quality
 			}
+			subtitle := playerSubtitle(source, stream)
 
 			mpvTitle := fmt.Sprintf("%s - %s", selectedAnimeName, episodeNames[selectedEpisodeIndex])
 			if isMovie {
 				mpvTitle = selectedAnimeName
 			}
 
-			cmd, _, err := player.PlayVLC(player.VLCParams{
-				Url:         urls[selectedResolutionIdx],
-				SubtitleUrl: &subtitle,
+			// Yarıda bırakılan bölüm kaldığı yerden başlatılır
+			startTime := resumePosition(cfx.history, history.Key(source.Source(), selectedAnimeID, selectedAnimeSlug), episodes[selectedEpisodeIndex])
+
+			session, err := cfx.player.Play(player.Params{
+				URL:         stream.URL,
 				Title:       mpvTitle,
-				VLCPath:     "",
+				SubtitleURL: subtitle,
+				Headers:     stream.Headers,
+				StartTime:   startTime,
 			})
 			if !utils.CheckErr(err, logger) {
 				return source, selectedSource, false
 			}
 
 			if !disableRPC {
-				go updateDiscordRPC(episodeNames, selectedEpisodeIndex, selectedAnimeName, selectedSource, posterURL, myAnimeListURL, logger, &loggedIn)
+				episodeIndex := selectedEpisodeIndex
+				go updateDiscordRPC(episodeNames, episodeIndex, selectedAnimeName, selectedSource, posterURL, myAnimeListURL, false, logger, &loggedIn)
+				go watchPauseEvents(session, func(paused bool) {
+					updateDiscordRPC(episodeNames, episodeIndex, selectedAnimeName, selectedSource, posterURL, myAnimeListURL, paused, logger, &loggedIn)
+				})
 			}
 
-			err = cmd.Wait()
-			if err != nil {
-				fmt.Println("VLC çalışırken hata:", err)
-			} else {
-				// Record the last watched episode
-				animeIdentifier := selectedAnimeName // Use anime name as identifier for history
-				if selectedAnimeSlug != "" {
-					animeIdentifier = selectedAnimeSlug
-				} else if selectedAnimeID != 0 {
-					animeIdentifier = strconv.Itoa(selectedAnimeID)
+			next, hasNext := bingeNextIndex(episodes, selectedEpisodeIndex)
+			if *cfx.binge && hasNext {
+				preload = preloadStreams(cfx.sessionContext(), source, episodes, next, selectedAnimeID, selectedAnimeSlug, isMovie, selectedFansubID)
+			}
+
+			// Terminaldeki Ctrl+C oynatıcıya da gider ve oynatıcı hata koduyla kapanır; bu durumda
+			// da konum kaydedildikten sonra oturum sonlanır
+			status, err := session.Wait()
+			if err != nil && !cfx.interrupted() {
+				fmt.Println("Oynatıcı çalışırken hata:", err)
+			}
+			if err == nil || status.Known() {
+				// İzlenen bölüm ve oynatma konumu geçmişe kaydedilir
+				poster := posterURL
+				if poster == "anitrcli" {
+					poster = ""
+				}
+				record := playbackRecord(episodes[selectedEpisodeIndex], status, *cfx.watchedPercent)
+				cfx.history.RecordEpisode(history.Show{
+					Source:     source.Source(),
+					AnimeID:    selectedAnimeID,
+					Slug:       selectedAnimeSlug,
+					Title:      selectedAnimeName,
+					PosterURL:  poster,
+					IsMovie:    isMovie,
+					Fansub:     selectedFansubID,
+					Resolution: selectedResolution,
+				}, record)
+				if err := cfx.history.Save(); err != nil {
+					logger.LogError(fmt.Errorf("failed to save history: %w", err))
+				}
+
+				// Bölüm sonuna kadar izlendiyse geri sayımdan sonra sıradaki bölüm oynatılır
+				if *cfx.binge && !isMovie && record.Completed && !cfx.interrupted() {
+					if !hasNext {
+						fmt.Println("Sezonun son bölümü izlendi, otomatik oynatma durdu.")
+						time.Sleep(1500 * time.Millisecond)
+						break
+					}
+					appCtx := App{uiMode: &uiMode, rofiFlags: &rofiFlags, logger: logger, history: cfx.history, ctx: cfx.ctx}
+					if playNext, err := showCountdown(appCtx, fmt.Sprintf("Sıradaki: %s", episodeNames[next])); utils.CheckErr(err, logger) && playNext {
+						selectedEpisodeIndex = next
+						autoPlay = true
+					}
 				}
-				cfx.history.SetLastWatchedEpisode(animeIdentifier, episodes[selectedEpisodeIndex].Number)
 			}
 
 		case "Çözünürlük seç":
-			data, _, err := updateWatchAPI(
-				strings.ToLower(selectedSource),
-				episodes,
-				selectedEpisodeIndex,
-				selectedAnimeID,
-				selectedSeasonIndex,
-				selectedFansubIdx,
-				isMovie,
-				&selectedAnimeSlug,
-			)
+			streams, err := loadStreams(cfx, source, episodes, selectedEpisodeIndex, selectedAnimeID, selectedAnimeSlug, isMovie, selectedFansubID)
+			if errors.Is(err, utils.ErrCancelled) || cfx.interrupted() {
+				continue
+			}
 			if err != nil {
 				fmt.Printf("[!] Çözünürlükler yüklenemedi.\n")
 				time.Sleep(1000 * time.Millisecond)
 				continue
 			}
-			labels := data["labels"].([]string)
-			appCtx := App{uiMode: &uiMode, rofiFlags: &rofiFlags, logger: logger}
+			labels := models.Qualities(streams)
+			appCtx := App{uiMode: &uiMode, rofiFlags: &rofiFlags, logger: logger, history: cfx.history, ctx: cfx.ctx}
 			selectedSlice, err := showSelection(appCtx, labels, "Çözünürlük seç ", "", nil)
 			if !utils.CheckErr(err, logger) {
 				continue
@@ -497,11 +517,11 @@ func playAnimeLoop(
 				time.Sleep(1500 * time.Millisecond)
 				continue
 			}
-			selectedResolutionIdx = slices.Index(labels, selected)
+			selectedResolution = selected
 
 		case "Bölüm seç":
 			episodeMenu := append([]string{"Geri"}, episodeNames...)
-			appCtx := App{uiMode: &uiMode, rofiFlags: &rofiFlags, logger: logger}
+			appCtx := App{uiMode: &uiMode, rofiFlags: &rofiFlags, logger: logger, history: cfx.history, ctx: cfx.ctx}
 			selectedSlice, err := showSelection(appCtx, episodeMenu, "Bölüm seç ", "", nil)
 			if !utils.CheckErr(err, logger) || len(selectedSlice) == 0 {
 				continue
@@ -516,30 +536,25 @@ func playAnimeLoop(
 				continue
 			}
 			selectedEpisodeIndex = slices.Index(episodeNames, selected)
-			if !isMovie && selectedEpisodeIndex >= 0 && selectedEpisodeIndex < len(episodes) {
-				selectedSeasonIndex = int(episodes[selectedEpisodeIndex].Extra["season_num"].(float64)) - 1
-			}
 
 		case "Fansub seç":
 			fansubNames := []string{}
 
-			if strings.ToLower(source.Source()) != "openanime" {
-				fmt.Println("[!] Bu seçenek sadece OpenAnime için geçerlidir.")
+			if !sourceCapabilities(source).Fansubs {
+				fmt.Println("[!] Bu kaynak fansub seçimini desteklemiyor.")
 				time.Sleep(1500 * time.Millisecond)
 				continue
 			}
 
-			_, fansubData, err := updateWatchAPI(
-				strings.ToLower(selectedSource),
-				episodes,
-				selectedEpisodeIndex,
-				selectedAnimeID,
-				selectedSeasonIndex,
-				selectedFansubIdx,
-				isMovie,
-				&selectedAnimeSlug,
-			)
-
+			var fansubData []models.Fansub
+			err := loading(cfx, "Fansublar yükleniyor...", func(ctx context.Context) error {
+				var err error
+				fansubData, err = fetchFansubs(ctx, source, episodes[selectedEpisodeIndex], selectedAnimeID, selectedAnimeSlug)
+				return err
+			})
+			if errors.Is(err, utils.ErrCancelled) || cfx.interrupted() {
+				continue
+			}
 			if err != nil {
 				fmt.Printf("[!] Fansublar yüklenemedi.\n")
 				time.Sleep(1000 * time.Millisecond)
@@ -552,7 +567,7 @@ func playAnimeLoop(
 				}
 			}
 
-			appCtx := App{uiMode: &uiMode, rofiFlags: &rofiFlags, logger: logger}
+			appCtx := App{uiMode: &uiMode, rofiFlags: &rofiFlags, logger: logger, history: cfx.history, ctx: cfx.ctx}
 			selectedSlice, err := showSelection(appCtx, fansubNames, "Fansub seç ", "", nil)
 			if !utils.CheckErr(err, logger) {
 				continue
@@ -566,60 +581,89 @@ func playAnimeLoop(
 				time.Sleep(1500 * time.Millisecond)
 				continue
 			}
-			selectedFansubIdx = slices.Index(fansubNames, selected)
+			for _, fansub := range fansubData {
+				if fansub.Name != nil && *fansub.Name == selected && fansub.ID != nil {
+					selectedFansubID = *fansub.ID
+					fansubLabel = *fansub.Name
+				}
+			}
 
 		case "İndir":
 			if isMovie {
 				// Handle single movie download
-				data, _, err := updateWatchAPI(
-					strings.ToLower(selectedSource), episodes, 0, selectedAnimeID, 0, selectedFansubIdx, isMovie, &selectedAnimeSlug,
-				)
-				if err != nil {
-					fmt.Printf("[!] İndirme bağlantıları yüklenemedi: %s\n", err)
-					time.Sleep(1500 * time.Millisecond)
+				streams, err := loadStreams(cfx, source, episodes, 0, selectedAnimeID, selectedAnimeSlug, isMovie, selectedFansubID)
+				if errors.Is(err, utils.ErrCancelled) || cfx.interrupted() {
 					continue
 				}
-				labels := data["labels"].([]string)
-				urls := data["urls"].([]string)
-				if len(urls) == 0 {
-					fmt.Println("Bu film için indirme bağlantısı bulunamadı.")
+				if err != nil {
+					fmt.Printf("[!] İndirme bağlantıları yüklenemedi: %s\n", err)
 					time.Sleep(1500 * time.Millisecond)
 					continue
 				}
-				appCtx := App{uiMode: &uiMode, rofiFlags: &rofiFlags, logger: logger}
+				labels := models.Qualities(streams)
+				appCtx := App{uiMode: &uiMode, rofiFlags: &rofiFlags, logger: logger, history: cfx.history, ctx: cfx.ctx}
 				selectedResolutionLabelSlice, err := showSelection(appCtx, labels, "İndirilecek çözünürlüğü seç ", "", nil)
 				if !utils.CheckErr(err, logger) || len(selectedResolutionLabelSlice) == 0 {
 					continue
 				}
 				selectedResolutionLabel := selectedResolutionLabelSlice[0]
-				selectedDownloadIdx := slices.Index(labels, selectedResolutionLabel)
-				if selectedDownloadIdx == -1 {
+				downloadStream, found := models.SelectStream(streams, selectedResolutionLabel)
+				if !found {
 					fmt.Printf("[!] Geçersiz çözünürlük seçimi: %s\n", selectedResolutionLabel)
 					time.Sleep(1500 * time.Millisecond)
 					continue
 				}
-				downloadURL := urls[selectedDownloadIdx]
-				downloadDir := fmt.Sprintf("indirilenler/%s", selectedAnimeName)
-				if err := os.MkdirAll(downloadDir, 0755); err != nil {
-					fmt.Printf("Dizin oluşturulurken hata: %v\n", err)
+				downloadURL := downloadStream.URL
+				filename, err := downloadFilePath(naming.Fields{
+					Anime:   selectedAnimeName,
+					Title:   selectedAnimeName,
+					Fansub:  fansubLabel,
+					Quality: downloadStream.Quality,
+					Source:  source.Source(),
+				}, true)
+				if err != nil {
+					fmt.Printf("[!] %v\n", err)
 					time.Sleep(1500 * time.Millisecond)
 					continue
 				}
-				filename := fmt.Sprintf("%s/%s.mp4", downloadDir, selectedAnimeName)
-				fmt.Printf("İndiriliyor: %s\n", filename)
-				err = downloader.DownloadFile(downloadURL, filename)
-				if err != nil {
-					fmt.Printf("Dosya indirilirken hata: %v\n", err)
-				} else {
-					fmt.Println("İndirme tamamlandı!")
+				if existing := existingDownload(cfx.sessionContext(), filename, downloadURL, downloadStream.Headers, logger); existing != "" {
+					if !overwriteDownloads(appCtx, []string{existing}, logger) {
+						continue
+					}
+				}
+				// Film de kalıcı kuyruktan indirilir; yarıda kalırsa "downloads resume" ile devam edilebilir
+				store, queue := openPersistentQueue(logger)
+				job := downloader.Job{
+					Name:    selectedAnimeName,
+					URL:     downloadURL,
+					Path:    filename,
+					Quality: downloadStream.Quality,
+					Headers: downloadStream.Headers,
+				}
+				job.SubtitleURL, job.SubtitleLang = streamSubtitle(source, downloadStream)
+				enqueueDownload(store, queue, job, downloader.Origin{
+					Source:       source.Source(),
+					AnimeID:      selectedAnimeID,
+					Slug:         selectedAnimeSlug,
+					IsMovie:      true,
+					FansubID:     selectedFansubID,
+					Quality:      downloadStream.Quality,
+					SubtitleURL:  job.SubtitleURL,
+					SubtitleLang: job.SubtitleLang,
+					Headers:      job.Headers,
+				}, logger)
+
+				summary := queue.Run(cfx.sessionContext())
+				summary.Print(os.Stdout)
+				if !cfx.interrupted() {
+					time.Sleep(1500 * time.Millisecond)
 				}
-				time.Sleep(1500 * time.Millisecond)
 				continue
 			}
 
 			// Batch download for series
 			episodeMenu := append([]string{"Geri"}, episodeNames...)
-			appCtx := App{uiMode: &uiMode, rofiFlags: &rofiFlags, logger: logger}
+			appCtx := App{uiMode: &uiMode, rofiFlags: &rofiFlags, logger: logger, history: cfx.history, ctx: cfx.ctx}
 			selectedEpisodeTitles, err := showSelection(appCtx, episodeMenu, "İndirilecek bölümleri seç (Space ile işaretle, Enter ile onayla)", "multi-select", nil)
 			if !utils.CheckErr(err, logger) || len(selectedEpisodeTitles) == 0 {
 				continue
@@ -647,20 +691,16 @@ func playAnimeLoop(
 				continue
 			}
 
-			data, _, err := updateWatchAPI(
-				strings.ToLower(selectedSource), episodes, selectedEpisodeIndex, selectedAnimeID, selectedSeasonIndex, selectedFansubIdx, isMovie, &selectedAnimeSlug,
-			)
-			if err != nil {
-				fmt.Printf("[!] Çözünürlükler yüklenemedi: %s\n", err)
-				time.Sleep(1500 * time.Millisecond)
+			streams, err := loadStreams(cfx, source, episodes, selectedEpisodeIndex, selectedAnimeID, selectedAnimeSlug, isMovie, selectedFansubID)
+			if errors.Is(err, utils.ErrCancelled) || cfx.interrupted() {
 				continue
 			}
-			labels := data["labels"].([]string)
-			if len(labels) == 0 {
-				fmt.Println("Bu anime için indirme bağlantısı bulunamadı.")
+			if err != nil {
+				fmt.Printf("[!] Çözünürlükler yüklenemedi: %s\n", err)
 				time.Sleep(1500 * time.Millisecond)
 				continue
 			}
+			labels := models.Qualities(streams)
 
 			selectedResolutionLabelsSlice, err := showSelection(appCtx, labels, "Tüm bölümler için çözünürlüğü seç ", "", nil)
 			if !utils.CheckErr(err, logger) || len(selectedResolutionLabelsSlice) == 0 {
@@ -668,59 +708,105 @@ func playAnimeLoop(
 			}
 			selectedResolutionLabel := selectedResolutionLabelsSlice[0]
 
-			for _, epIdx := range epsToDownload {
+			// Kuyruk kaydedilir; program kapanırsa "downloads resume" ile devam edilebilir.
+			// Bağlantılar sırayla alınır, indirmeler kuyrukta paralel çalışır
+			store, queue := openPersistentQueue(logger)
+			type pendingDownload struct {
+				job      downloader.Job
+				origin   downloader.Origin
+				existing string
+			}
+			var pending []pendingDownload
+			for i, epIdx := range epsToDownload {
 				episode := episodes[epIdx]
-				fmt.Printf("İndiriliyor: %s - %s...\n", selectedAnimeName, episode.Title)
+				job := downloader.Job{Name: episode.Title}
+				fmt.Printf("Bağlantılar alınıyor (%d/%d): %s\n", i+1, len(epsToDownload), episode.Title)
 
-				currentEpisodeWatchData, _, err := updateWatchAPI(
-					strings.ToLower(selectedSource), episodes, epIdx, selectedAnimeID, int(episode.Extra["season_num"].(float64))-1, selectedFansubIdx, isMovie, &selectedAnimeSlug,
-				)
+				var currentEpisodeStreams []models.Stream
+				currentEpisodeStreams, err := fetchStreams(cfx.sessionContext(), source, episodes, epIdx, selectedAnimeID, selectedAnimeSlug, isMovie, selectedFansubID)
+				if cfx.interrupted() {
+					break
+				}
 				if err != nil {
-					fmt.Printf("[!] Bölüm %s için indirme bağlantıları yüklenemedi: %s\n", episode.Title, err)
+					queue.Skip(job, fmt.Errorf("indirme bağlantıları yüklenemedi: %w", err))
 					continue
 				}
 
-				currentEpisodeUrls := currentEpisodeWatchData["urls"].([]string)
-				currentEpisodeLabels := currentEpisodeWatchData["labels"].([]string)
-
-				currentDownloadURL := ""
-				for i, label := range currentEpisodeLabels {
-					if label == selectedResolutionLabel {
-						currentDownloadURL = currentEpisodeUrls[i]
-						break
-					}
+				currentStream, found := models.SelectStream(currentEpisodeStreams, selectedResolutionLabel)
+				if currentStream.URL == "" {
+					queue.Skip(job, fmt.Errorf("video akışı bulunamadı"))
+					continue
 				}
-
-				if currentDownloadURL == "" {
-					if len(currentEpisodeUrls) > 0 {
-						currentDownloadURL = currentEpisodeUrls[0]
-						fmt.Printf("[!] '%s' çözünürlüğü bulunamadı, '%s' indiriliyor.\n", selectedResolutionLabel, currentEpisodeLabels[0])
-					} else {
-						fmt.Printf("[!] Bölüm %s için indirme bağlantısı bulunamadı.\n", episode.Title)
-						continue
-					}
+				if !found {
+					fmt.Printf("[!] '%s' çözünürlüğü bulunamadı, '%s' indirilecek.\n", selectedResolutionLabel, currentStream.Quality)
 				}
-
-				downloadDir := fmt.Sprintf("indirilenler/%s", selectedAnimeName)
-				if err := os.MkdirAll(downloadDir, 0755); err != nil {
-					fmt.Printf("Dizin oluşturulurken hata: %v\n", err)
+				job.URL, job.Quality, job.Headers = currentStream.URL, currentStream.Quality, currentStream.Headers
+				job.Path, err = downloadFilePath(naming.Fields{
+					Anime:    selectedAnimeName,
+					Title:    episode.Title,
+					Season:   episode.Season,
+					Episode:  episode.Number,
+					Absolute: episode.AbsoluteNumber,
+					Fansub:   fansubLabel,
+					Quality:  currentStream.Quality,
+					Source:   source.Source(),
+				}, isMovie)
+				if err != nil {
+					queue.Skip(job, err)
 					continue
 				}
-				filename := fmt.Sprintf("%s/%s.mp4", downloadDir, episode.Title)
-				err = downloader.DownloadFile(currentDownloadURL, filename)
-				if err != nil {
-					fmt.Printf("[!] Dosya indirilirken hata (%s): %v\n", filename, err)
-				} else {
-					fmt.Printf("İndirme tamamlandı: %s\n", filename)
+				job.SubtitleURL, job.SubtitleLang = streamSubtitle(source, currentStream)
+				pending = append(pending, pendingDownload{
+					job: job,
+					origin: downloader.Origin{
+						Source:         source.Source(),
+						AnimeID:        selectedAnimeID,
+						Slug:           selectedAnimeSlug,
+						IsMovie:        isMovie,
+						FansubID:       selectedFansubID,
+						Season:         episode.Season,
+						Episode:        episode.Number,
+						AbsoluteNumber: episode.AbsoluteNumber,
+						Quality:        currentStream.Quality,
+						SubtitleURL:    job.SubtitleURL,
+						SubtitleLang:   job.SubtitleLang,
+						Headers:        job.Headers,
+					},
+					existing: existingDownload(cfx.sessionContext(), job.Path, job.URL, job.Headers, logger),
+				})
+			}
+
+			if cfx.interrupted() {
+				continue
+			}
+
+			// Daha önce indirilmiş bölümler tek seferde sorulur; varsayılan olarak atlanır
+			var existing []string
+			for _, p := range pending {
+				if p.existing != "" {
+					existing = append(existing, p.existing)
 				}
-				time.Sleep(500 * time.Millisecond)
 			}
-			fmt.Println("Tüm indirmeler tamamlandı!")
-			time.Sleep(1500 * time.Millisecond)
+			overwrite := len(existing) > 0 && overwriteDownloads(appCtx, existing, logger)
+			for _, p := range pending {
+				if p.existing != "" && !overwrite {
+					queue.Skip(p.job, fmt.Errorf("zaten indirilmiş: %s", p.existing))
+					continue
+				}
+				enqueueDownload(store, queue, p.job, p.origin, logger)
+			}
+
+			summary := queue.Run(cfx.sessionContext())
+			summary.Print(os.Stdout)
+			if cfx.interrupted() {
+				continue
+			}
+			fmt.Println("\nDevam etmek için Enter'a basın...")
+			fmt.Scanln()
 
 		case "Anime ara":
 			for {
-				appCtx := App{uiMode: &uiMode, rofiFlags: &rofiFlags, logger: logger}
+				appCtx := App{uiMode: &uiMode, rofiFlags: &rofiFlags, logger: logger, history: cfx.history, ctx: cfx.ctx}
 				choices, err := showSelection(appCtx, []string{"Bu kaynakla devam et", "Kaynak değiştir", "Çık"}, fmt.Sprintf("Arama kaynağı: %s", selectedSource), "", nil)
 				if !utils.CheckErr(err, logger) {
 					continue
@@ -754,7 +840,7 @@ func playAnimeLoop(
 	}
 }
 
-	func updateDiscordRPC(episodeNames []string, selectedEpisodeIndex int, selectedAnimeName, selectedSource, posterURL, myAnimeListURL string, logger *utils.Logger, loggedIn *bool) {
+func updateDiscordRPC(episodeNames []string, selectedEpisodeIndex int, selectedAnimeName, selectedSource, posterURL, myAnimeListURL string, paused bool, logger *utils.Logger, loggedIn *bool) {
 	if !*loggedIn {
 		var err error
 		*loggedIn, err = rpc.ClientLogin()
@@ -766,15 +852,16 @@ func playAnimeLoop(
 
 	var err2 error // Declare err2
 	*loggedIn, err2 = rpc.DiscordRPC(internal.RPCParams{
-		AnimeTitle:    selectedAnimeName,
-		EpisodeTitle:  episodeNames[selectedEpisodeIndex],
+		AnimeTitle:     selectedAnimeName,
+		EpisodeTitle:   episodeNames[selectedEpisodeIndex],
 		CurrentEpisode: selectedEpisodeIndex + 1,
 		TotalEpisodes:  len(episodeNames),
-		LargeImage:    posterURL, // Use posterURL for LargeImage
-		LargeText:     selectedAnimeName,
-		SmallImage:    strings.ToLower(selectedSource), // Use selectedSource for SmallImage
-		SmallText:     selectedSource,                  // Use selectedSource for SmallText
-		MyAnimeListURL: myAnimeListURL, // Pass MyAnimeListURL
+		LargeImage:     posterURL, // Use posterURL for LargeImage
+		LargeText:      selectedAnimeName,
+		SmallImage:     strings.ToLower(selectedSource), // Use selectedSource for SmallImage
+		SmallText:      selectedSource,                  // Use selectedSource for SmallText
+		MyAnimeListURL: myAnimeListURL,                  // Pass MyAnimeListURL
+		Paused:         paused,
 	}, *loggedIn) // Pass *loggedIn as the second argument
 
 	if err2 != nil {
@@ -782,14 +869,370 @@ func playAnimeLoop(
 	}
 }
 
+// bingeCountdownSeconds, otomatik oynatmada sıradaki bölüm başlamadan önce beklenen süredir.
+const bingeCountdownSeconds = 5
+
+// bingeLabel, izleme menüsündeki otomatik oynatma seçeneğinin etiketini döner.
+func bingeLabel(enabled bool) string {
+	if enabled {
+		return "Otomatik oynatma: Açık"
+	}
+	return "Otomatik oynatma: Kapalı"
+}
+
+// bingeNextIndex, otomatik oynatmada sıradaki bölümün indeksini döner.
+// Otomatik oynatma sezon sonunda durduğundan sıradaki bölüm başka sezondaysa false döner.
+func bingeNextIndex(episodes []models.Episode, index int) (int, bool) {
+	next := index + 1
+	if next >= len(episodes) || episodes[next].Season != episodes[index].Season {
+		return 0, false
+	}
+	return next, true
+}
+
+// showCountdown, iptal edilebilir geri sayımı gösterir ve sıradaki bölümün oynatılıp oynatılmayacağını döner.
+func showCountdown(cfx App, label string) (bool, error) {
+	return ui.Countdown(internal.UiParams{
+		Mode:      *cfx.uiMode,
+		Label:     label,
+		RofiFlags: cfx.rofiFlags,
+		Logger:    cfx.logger,
+	}, bingeCountdownSeconds)
+}
+
+// streamPreload, bir bölüm için arka planda alınan video akışlarını tutar.
+type streamPreload struct {
+	index    int
+	fansubID string
+	done     chan struct{}
+	streams  []models.Stream
+	err      error
+}
+
+// preloadStreams, verilen bölümün akışlarını arka planda almaya başlar.
+func preloadStreams(ctx context.Context, source models.AnimeSource, episodes []models.Episode, index, id int, slug string, isMovie bool, fansubID string) *streamPreload {
+	p := &streamPreload{index: index, fansubID: fansubID, done: make(chan struct{})}
+	go func() {
+		p.streams, p.err = fetchStreams(ctx, source, episodes, index, id, slug, isMovie, fansubID)
+		close(p.done)
+	}()
+	return p
+}
+
+// result, önceden alınan akışlar istenen bölüm ve fansub içinse tamamlanmasını bekleyip döner.
+func (p *streamPreload) result(index int, fansubID string) (*streamPreload, bool) {
+	if p == nil || p.index != index || p.fansubID != fansubID {
+		return nil, false
+	}
+	<-p.done
+	return p, true
+}
+
+// watchPauseEvents, oynatıcı IPC destekliyorsa duraklatma olaylarında onPause'u çağırır.
+// Oynatıcı kapanınca olay kanalı kapandığından döngü kendiliğinden biter.
+func watchPauseEvents(session *player.Session, onPause func(paused bool)) {
+	events := session.Events()
+	if events == nil {
+		return
+	}
+	for event := range events {
+		if event.Name == "pause" {
+			onPause(event.Paused)
+		}
+	}
+}
+
+// animePosterURL, Discord RPC'de gösterilecek posteri döner.
+// Poster geçerli bir görsel değilse varsayılan "anitrcli" görseli kullanılır.
+func animePosterURL(anime models.Anime) string {
+	if !utils.IsValidImage(anime.ImageURL) {
+		return "anitrcli"
+	}
+	return anime.ImageURL
+}
+
+// animeMyAnimeListURL, anime için MyAnimeList bağlantısını döner.
+func animeMyAnimeListURL(anime models.Anime) string {
+	if anime.ID != nil && anime.Slug != nil {
+		return fmt.Sprintf("https://myanimelist.net/anime/%d/%s", *anime.ID, *anime.Slug)
+	}
+	return fmt.Sprintf("https://myanimelist.net/search/all?q=%s&cat=anime", strings.ReplaceAll(anime.Title, " ", "+"))
+}
+
+// watchState, izleme döngüsünün hangi bölüm ve tercihlerle başlayacağını belirtir.
+type watchState struct {
+	episodeIndex int    // Başlangıçta seçili bölümün indeksi
+	fansubID     string // Başlangıçta seçili fansub
+	resolution   string // Başlangıçta seçili çözünürlük
+	autoPlay     bool   // Menü gösterilmeden seçili bölüm oynatılsın mı
+}
+
+// episodeRecord, oynatılan bölüm için izleme geçmişi kaydını oluşturur.
+func episodeRecord(ep models.Episode) history.EpisodeRecord {
+	return history.EpisodeRecord{
+		Season:         ep.Season,
+		Episode:        ep.Number,
+		AbsoluteNumber: ep.AbsoluteNumber,
+		Title:          ep.Title,
+		Completed:      true,
+	}
+}
+
+// playbackRecord, oynatıcının bildirdiği konumla bölümün geçmiş kaydını oluşturur.
+// Bölüm, oynatma yüzdesi watchedPercent'e ulaştıysa izlendi sayılır.
+// Oynatıcıdan konum alınamadıysa eskisi gibi izlendi kabul edilir.
+func playbackRecord(ep models.Episode, status player.PlaybackStatus, watchedPercent int) history.EpisodeRecord {
+	record := episodeRecord(ep)
+	if !status.Known() {
+		return record
+	}
+	record.Position = status.Position
+	record.Duration = status.Duration
+	record.Completed = status.Percent() >= float64(watchedPercent)
+	return record
+}
+
+// formatPosition, saniye cinsinden konumu "dd:ss" veya "sa:dd:ss" biçiminde döner.
+func formatPosition(seconds float64) string {
+	total := int(seconds)
+	h, m, sec := total/3600, total%3600/60, total%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, sec)
+	}
+	return fmt.Sprintf("%02d:%02d", m, sec)
+}
+
+// resumePosition, bölüm daha önce yarıda bırakıldıysa kalınan konumu (saniye) döner.
+func resumePosition(hist *history.History, key string, ep models.Episode) float64 {
+	show, ok := hist.Show(key)
+	if !ok {
+		return 0
+	}
+	record, ok := show.Episode(ep.Season, ep.Number, ep.AbsoluteNumber)
+	if !ok || record.Completed {
+		return 0
+	}
+	return record.Position
+}
+
+// nextEpisodeIndex, geçmişte kayıtlı son bölümden sonraki bölümün indeksini döner.
+// Son bölüm yarıda bırakıldıysa aynı bölümün indeksi döner.
+// Sezon bilgisi olmayan (eski sürümden taşınan) kayıtlar mutlak bölüm numarasıyla, sezonu
+// bilinmeyen OpenAnime kayıtları ise sezon içi numarasıyla ilk eşleşen bölüme bağlanır.
+// Son izlenen bölüm listenin sonundaysa ikinci değer false olur ve son bölüm döner.
+func nextEpisodeIndex(episodes []models.Episode, last history.EpisodeRecord) (int, bool) {
+	for i, ep := range episodes {
+		matched := ep.AbsoluteNumber == last.AbsoluteNumber
+		switch {
+		case last.Season > 0:
+			matched = ep.Season == last.Season && ep.Number == last.Episode
+		case last.SeasonUnknown():
+			matched = ep.Number == last.Episode
+		}
+		if matched {
+			if !last.Completed {
+				return i, true
+			}
+			if i+1 < len(episodes) {
+				return i + 1, true
+			}
+			return i, false
+		}
+	}
+	return 0, true
+}
+
+// ambiguousEpisode, sezonu bilinmeyen kaydın sezon içi numarasının birden fazla sezonda
+// bulunup bulunmadığını döner; bu durumda hangi bölümde kalındığı bilinemez.
+func ambiguousEpisode(episodes []models.Episode, last history.EpisodeRecord) bool {
+	if !last.SeasonUnknown() {
+		return false
+	}
+	matches := 0
+	for _, ep := range episodes {
+		if ep.Number == last.Episode {
+			matches++
+		}
+	}
+	return matches > 1
+}
+
+// continueWatching, geçmişteki son izlenen animeleri listeler ve seçilen animenin
+// bir sonraki bölümünü, o anime için kullanılan kaynak, fansub ve çözünürlükle oynatır.
+// Kullanıcı listeden çıkarsa veya geçmiş boşsa false döner.
+func continueWatching(cfx *App) bool {
+	var (
+		shows  []*history.Show
+		labels []string
+	)
+	for _, show := range cfx.history.Recent() {
+		sourceEntry, ok := sources.Get(show.Source)
+		if !ok {
+			continue
+		}
+		label := fmt.Sprintf("%s (%s)", show.Title, sourceEntry.Label)
+		if last, ok := show.LastEpisode(); ok {
+			state := "izlendi"
+			if !last.Completed {
+				state = fmt.Sprintf("%s'da kaldı", formatPosition(last.Position))
+			}
+			switch {
+			case show.IsMovie:
+				if !last.Completed {
+					label = fmt.Sprintf("%s - %s (%s)", show.Title, state, sourceEntry.Label)
+				}
+			case last.Season > 0:
+				label = fmt.Sprintf("%s - %d. sezon %d. bölüm %s (%s)", show.Title, last.Season, last.Episode, state, sourceEntry.Label)
+			case last.SeasonUnknown():
+				label = fmt.Sprintf("%s - %d. bölüm %s (%s)", show.Title, last.Episode, state, sourceEntry.Label)
+			default:
+				label = fmt.Sprintf("%s - %d. bölüm %s (%s)", show.Title, last.AbsoluteNumber, state, sourceEntry.Label)
+			}
+		}
+		shows = append(shows, show)
+		labels = append(labels, label)
+	}
+
+	if len(shows) == 0 {
+		fmt.Println("[!] Devam edilecek anime bulunamadı.")
+		time.Sleep(1500 * time.Millisecond)
+		return false
+	}
+
+	selectedSlice, err := showSelection(*cfx, append([]string{"Geri"}, labels...), "Devam et ", "", nil)
+	if !utils.CheckErr(err, cfx.logger) || len(selectedSlice) == 0 || selectedSlice[0] == "Geri" {
+		return false
+	}
+	idx := slices.Index(labels, selectedSlice[0])
+	if idx == -1 {
+		return false
+	}
+	show := shows[idx]
+
+	sourceEntry, _ := sources.Get(show.Source)
+	anime := models.Anime{Title: show.Title, ImageURL: show.PosterURL, Source: show.Source}
+	if show.AnimeID != 0 {
+		anime.ID = utils.Ptr(show.AnimeID)
+	}
+	if show.Slug != "" {
+		anime.Slug = utils.Ptr(show.Slug)
+	}
+	if show.IsMovie {
+		anime.TitleType = utils.Ptr("movie")
+	}
+
+	var (
+		episodes     []models.Episode
+		episodeNames []string
+		isMovie      bool
+	)
+	err = loading(*cfx, "Bölümler yükleniyor...", func(ctx context.Context) error {
+		var err error
+		episodes, episodeNames, isMovie, err = getEpisodesAndNames(ctx, sourceEntry.Source, show.IsMovie, anime, cfx.logger)
+		return err
+	})
+	if cfx.interrupted() || errors.Is(err, utils.ErrCancelled) || !utils.CheckErr(err, cfx.logger) {
+		return false
+	}
+
+	start := watchState{fansubID: show.Fansub, resolution: show.Resolution, autoPlay: true}
+	if last, ok := show.LastEpisode(); ok && !isMovie {
+		next, ok := nextEpisodeIndex(episodes, last)
+		switch {
+		case ambiguousEpisode(episodes, last):
+			fmt.Printf("[!] %d. bölümün hangi sezona ait olduğu bilinmiyor, lütfen bölümü seçin.\n", last.Episode)
+			time.Sleep(1500 * time.Millisecond)
+			start.autoPlay = false
+		case !ok:
+			fmt.Println("[!] Son bölüm zaten izlenmiş.")
+			time.Sleep(1500 * time.Millisecond)
+			start.autoPlay = false
+		}
+		start.episodeIndex = next
+	}
+
+	cfx.source = utils.Ptr(sourceEntry.Source)
+	cfx.selectedSource = utils.Ptr(sourceEntry.Label)
+
+	selectedAnimeID, selectedAnimeSlug := getAnimeIDs(anime)
+	newSource, newSelectedSource, _ := playAnimeLoop(
+		*cfx,
+		*cfx.source, *cfx.selectedSource, episodes, episodeNames,
+		selectedAnimeID, selectedAnimeSlug, anime.Title,
+		isMovie, *cfx.uiMode, *cfx.rofiFlags,
+		animePosterURL(anime), *cfx.disableRPC, cfx.logger, animeMyAnimeListURL(anime), start,
+	)
+	cfx.source = &newSource
+	cfx.selectedSource = &newSelectedSource
+	return true
+}
+
 type App struct {
 	source         *models.AnimeSource
 	selectedSource *string
 	uiMode         *string
 	rofiFlags      *string
 	disableRPC     *bool
+	watchedPercent *int
+	binge          *bool
+	player         player.Player
 	logger         *utils.Logger
 	history        *history.History // Add history to App struct
+
+	// ctx, oturumun bağlamıdır; Ctrl+C veya SIGINT ile interrupt çağrılınca iptal edilir
+	ctx       context.Context
+	interrupt context.CancelFunc
+}
+
+// interrupted, oturumun Ctrl+C veya SIGINT ile iptal edilip edilmediğini döner.
+func (cfx App) interrupted() bool {
+	return cfx.ctx != nil && cfx.ctx.Err() != nil
+}
+
+// sessionContext, oturumun bağlamını döner; oturumu olmayan App değerleri için boş bağlam döner.
+// Oturum sürerken gelen SIGINT bu bağlamla yapılan istekleri iptal eder; program kapanmaz,
+// runMain geçmişi kaydedip çıkar.
+func (cfx App) sessionContext() context.Context {
+	if cfx.ctx == nil {
+		return context.Background()
+	}
+	return cfx.ctx
+}
+
+// signalContext, Ctrl+C (SIGINT) veya SIGTERM gelince iptal edilen bir bağlam döner. İlk
+// sinyalden sonra varsayılan davranış geri gelir; böylece yanıt vermeyen bir adımda ikinci
+// Ctrl+C programı hemen kapatır.
+func signalContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+	context.AfterFunc(ctx, stop)
+	return ctx, stop
+}
+
+// loading, fn'i yükleniyor göstergesiyle çalıştırır. Kullanıcı Esc ile iptal ederse
+// utils.ErrCancelled döner; Ctrl+C ise SIGINT gibi tüm oturumu iptal eder.
+func loading(cfx App, label string, fn func(ctx context.Context) error) error {
+	err := ui.Spinner(cfx.sessionContext(), internal.UiParams{
+		Mode:      *cfx.uiMode,
+		RofiFlags: cfx.rofiFlags,
+		Label:     label,
+		Logger:    cfx.logger,
+	}, fn)
+	if errors.Is(err, utils.ErrQuit) && cfx.interrupt != nil {
+		cfx.interrupt()
+		return context.Canceled
+	}
+	return err
+}
+
+// loadStreams, bölümün video akışlarını yükleniyor göstergesiyle alır.
+func loadStreams(cfx App, source models.AnimeSource, episodes []models.Episode, index, id int, slug string, isMovie bool, fansubID string) ([]models.Stream, error) {
+	var streams []models.Stream
+	err := loading(cfx, "Bağlantılar alınıyor...", func(ctx context.Context) error {
+		var err error
+		streams, err = fetchStreams(ctx, source, episodes, index, id, slug, isMovie, fansubID)
+		return err
+	})
+	return streams, err
 }
 
 func showSelection(cfx App, list []string, label string, promptType string, data interface{}) ([]string, error) {
@@ -802,6 +1245,8 @@ func showSelection(cfx App, list []string, label string, promptType string, data
 		Data:      data,
 		Logger:    cfx.logger,
 	})
+	// Menü açıkken gelen SIGINT menü programını da kapatır; geçmiş kaydedilip çıkılır
+	exitIfInterrupted(&cfx)
 	if err != nil {
 		return nil, err
 	}
@@ -810,7 +1255,13 @@ func showSelection(cfx App, list []string, label string, promptType string, data
 
 func app(cfx *App) error {
 	for {
-		searchData, animeNames, animeTypes, _ := searchAnime(*cfx.source, *cfx.uiMode, *cfx.rofiFlags, cfx.logger)
+		if cfx.interrupted() {
+			return cfx.ctx.Err()
+		}
+		searchData, animeNames, animeTypes, _, err := searchAnime(*cfx)
+		if err != nil {
+			return err
+		}
 		isMovie := false
 		selectedAnime, isMovie, _ := selectAnime(animeNames, searchData, *cfx.uiMode, isMovie, *cfx.rofiFlags, animeTypes, cfx.logger)
 
@@ -832,16 +1283,25 @@ func app(cfx *App) error {
 
 			switch selectedAction {
 			case "Bölümleri Listele":
-				posterURL := selectedAnime.ImageURL
-				if !utils.IsValidImage(posterURL) {
-					posterURL = "anitrcli"
-				}
+				posterURL := animePosterURL(selectedAnime)
 
-				selectedAnimeID, selectedAnimeSlug := getAnimeIDs(*cfx.source, selectedAnime)
+				selectedAnimeID, selectedAnimeSlug := getAnimeIDs(selectedAnime)
 
-				episodes, episodeNames, isMovie, selectedSeasonIndex, err := getEpisodesAndNames(
-					*cfx.source, isMovie, selectedAnimeID, selectedAnimeSlug, selectedAnime.Title, cfx.logger,
+				var (
+					episodes     []models.Episode
+					episodeNames []string
 				)
+				err := loading(*cfx, "Bölümler yükleniyor...", func(ctx context.Context) error {
+					var err error
+					episodes, episodeNames, isMovie, err = getEpisodesAndNames(ctx, *cfx.source, isMovie, selectedAnime, cfx.logger)
+					return err
+				})
+				if cfx.interrupted() {
+					return cfx.ctx.Err()
+				}
+				if errors.Is(err, utils.ErrCancelled) {
+					continue
+				}
 
 				if err != nil {
 					cfx.logger.LogError(err)
@@ -869,19 +1329,14 @@ func app(cfx *App) error {
 					continue
 				}
 
-				myAnimeListURL := ""
-				if selectedAnime.ID != nil && selectedAnime.Slug != nil {
-					myAnimeListURL = fmt.Sprintf("https://myanimelist.net/anime/%d/%s", *selectedAnime.ID, *selectedAnime.Slug)
-				} else {
-					myAnimeListURL = fmt.Sprintf("https://myanimelist.net/search/all?q=%s&cat=anime", strings.ReplaceAll(selectedAnime.Title, " ", "+"))
-				}
+				myAnimeListURL := animeMyAnimeListURL(selectedAnime)
 
 				newSource, newSelectedSource, backPressed := playAnimeLoop(
 					*cfx, // Pass the App context
 					*cfx.source, *cfx.selectedSource, episodes, episodeNames,
 					selectedAnimeID, selectedAnimeSlug, selectedAnime.Title,
-					isMovie, selectedSeasonIndex, *cfx.uiMode, *cfx.rofiFlags,
-					posterURL, *cfx.disableRPC, cfx.logger, myAnimeListURL,
+					isMovie, *cfx.uiMode, *cfx.rofiFlags,
+					posterURL, *cfx.disableRPC, cfx.logger, myAnimeListURL, watchState{},
 				)
 
 				if newSource != *cfx.source || newSelectedSource != *cfx.selectedSource {
@@ -909,19 +1364,100 @@ func app(cfx *App) error {
 	}
 }
 
-func runMain(f *flags.Flags, uiMode string, logger *utils.Logger) {
+// newPlayer, bayraklarda seçilen oynatıcıyı oluşturur.
+// VLC için --player-path verilmemişse --vlc-path kullanılır.
+func newPlayer(f *flags.Flags) (player.Player, error) {
+	path := f.PlayerPath
+	if path == "" && strings.EqualFold(f.Player, "vlc") {
+		path = f.VLCPath
+	}
+	return player.New(f.Player, path, f.PlayerCmd)
+}
+
+// dataDir, izleme geçmişinin tutulduğu dizini döner.
+// Yapılandırmada dizin belirtilmemişse sistemin veri dizini kullanılır.
+func dataDir() (string, error) {
+	if appConfig.DataDir != "" {
+		return paths.Ensure(appConfig.DataDir, nil)
+	}
+	return paths.Ensure(paths.DataDir())
+}
+
+// downloadRoot, indirilen bölümlerin kök dizinini döner.
+// Yapılandırmada dizin belirtilmemişse sistemin indirme dizini kullanılır.
+func downloadRoot() string {
+	if appConfig.DownloadDir != "" {
+		return appConfig.DownloadDir
+	}
+	dir, err := paths.DownloadDir()
+	if err != nil {
+		return "indirilenler"
+	}
+	return dir
+}
+
+// downloadFilePath, ad şablonuna göre indirilecek dosyanın tam yolunu oluşturur ve dizinini hazırlar.
+func downloadFilePath(fields naming.Fields, isMovie bool) (string, error) {
+	template := appConfig.NameTemplate
+	if isMovie {
+		template = appConfig.MovieNameTemplate
+	}
+	if fields.Ext == "" {
+		fields.Ext = "mp4"
+	}
+
+	rel, err := naming.Render(template, fields)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(downloadRoot(), rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("dizin oluşturulamadı: %w", err)
+	}
+	return path, nil
+}
+
+// legacyHistoryPath, eski sürümlerin çalışma dizinine yazdığı izleme geçmişi dosyasıdır.
+var legacyHistoryPath = filepath.Join("data", "watched_history.json")
+
+// loadHistory, veri dizinini oluşturur ve izleme geçmişini yükler.
+// Çalışma dizinindeki eski data/watched_history.json bir kereliğine veri dizinine taşınır.
+func loadHistory() (*history.History, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	if appConfig.DataDir == "" {
+		newPath := filepath.Join(dir, "watched_history.json")
+		moved, err := paths.MigrateFile(legacyHistoryPath, newPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to migrate history: %w", err)
+		}
+		if moved {
+			fmt.Printf("İzleme geçmişi taşındı: %s -> %s\n", legacyHistoryPath, newPath)
+		}
+	}
+
+	hist, err := history.NewHistory(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize history: %w", err)
+	}
+	return hist, nil
+}
+
+func runMain(ctx context.Context, f *flags.Flags, uiMode string, logger *utils.Logger, continueFirst bool) {
 	disableRPC := f.DisableRPC
 
-	// Determine data directory for history
-	dataDir := "data" // Or a more robust path like os.UserHomeDir() + "/.anitr-cli"
-	if err := os.MkdirAll(dataDir, 0755); err != nil {
-		logger.LogError(fmt.Errorf("failed to create data directory: %w", err))
+	hist, err := loadHistory()
+	if err != nil {
+		logger.LogError(err)
 		os.Exit(1)
 	}
 
-	hist, err := history.NewHistory(dataDir)
+	mediaPlayer, err := newPlayer(f)
 	if err != nil {
-		logger.LogError(fmt.Errorf("failed to initialize history: %w", err))
+		fmt.Println(err)
 		os.Exit(1)
 	}
 
@@ -931,9 +1467,45 @@ func runMain(f *flags.Flags, uiMode string, logger *utils.Logger) {
 		uiMode:         &uiMode,
 		rofiFlags:      &f.RofiFlags,
 		disableRPC:     &disableRPC,
+		watchedPercent: &f.WatchedPercent,
+		binge:          &f.Binge,
+		player:         mediaPlayer,
 		logger:         logger,
 		history:        hist, // Initialize history
 	}
+	// Sinyal tüm oturum boyunca (menüler, oynatma ve indirmeler dahil) yakalanır
+	ctx, interrupt := context.WithCancel(ctx)
+	defer interrupt()
+	currentApp.ctx, currentApp.interrupt = ctx, interrupt
+
+	// Geçmiş varsa başlangıçta kaldığı yerden devam etme seçeneği sunulur
+	if continueFirst {
+		continueWatching(currentApp)
+		exitIfInterrupted(currentApp)
+	} else if len(hist.Shows) > 0 {
+		choices, err := showSelection(*currentApp, []string{"Devam et", "Anime ara", "Çık"}, "anitr-cli ", "", nil)
+		utils.FailIfErr(err, logger)
+		if len(choices) > 0 {
+			switch choices[0] {
+			case "Devam et":
+				continueWatching(currentApp)
+				exitIfInterrupted(currentApp)
+			case "Çık":
+				os.Exit(0)
+			}
+		}
+	}
+
+	// Yapılandırmada kaynak belirtilmişse ilk seferde kaynak menüsü atlanır
+	if currentApp.source == nil && appConfig.Source != "" {
+		entry, err := sources.Lookup(appConfig.Source)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		currentApp.selectedSource = utils.Ptr(entry.Label)
+		currentApp.source = utils.Ptr(entry.Source)
+	}
 
 	for {
 		if currentApp.source == nil {
@@ -943,6 +1515,7 @@ func runMain(f *flags.Flags, uiMode string, logger *utils.Logger) {
 		}
 
 		if err := app(currentApp); err != nil {
+			exitIfInterrupted(currentApp)
 			logger.LogError(err)
 			currentApp.source = nil
 		}
@@ -953,6 +1526,26 @@ func runMain(f *flags.Flags, uiMode string, logger *utils.Logger) {
 	}
 }
 
+// exitIfInterrupted, oturum Ctrl+C veya SIGINT ile iptal edildiyse izleme geçmişini
+// kaydedip programı kapatır.
+func exitIfInterrupted(cfx *App) {
+	if !cfx.interrupted() {
+		return
+	}
+	if err := cfx.history.Save(); err != nil {
+		cfx.logger.LogError(fmt.Errorf("failed to save history: %w", err))
+	}
+	fmt.Println("\n[!] İptal edildi.")
+	cfx.logger.Close()
+	os.Exit(130)
+}
+
+// downloadSource, download komutunun kullanacağı kaynağın adıdır (--source).
+var downloadSource string
+
+// downloadOverwrite, daha önce indirilmiş dosyanın yeniden indirilip indirilmeyeceğidir (--overwrite).
+var downloadOverwrite bool
+
 var downloadCmd = &cobra.Command{
 	Use:   "download [anime_title] [episode_number]",
 	Short: "Downloads an anime episode",
@@ -972,21 +1565,15 @@ var downloadCmd = &cobra.Command{
 		}
 		defer logger.Close()
 
-		// Determine data directory for history
-		dataDir := "data" // Or a more robust path like os.UserHomeDir() + "/.anitr-cli"
-		if err := os.MkdirAll(dataDir, 0755); err != nil {
-			logger.LogError(fmt.Errorf("failed to create data directory: %w", err))
-			os.Exit(1)
-		}
-
-		
-
-		
-
 		fmt.Printf("Attempting to download %s episode %d...\n", animeTitle, episodeNumber)
 
-		animeSource := animecix.AnimeCix{}
-		searchData, err := animeSource.GetSearchData(animeTitle)
+		entry, err := sources.Lookup(downloadSource)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		animeSource := entry.Source
+		searchData, err := animeSource.GetSearchData(cmd.Context(), animeTitle)
 		if err != nil {
 			fmt.Printf("Error searching for anime: %v\n", err)
 			os.Exit(1)
@@ -997,14 +1584,13 @@ var downloadCmd = &cobra.Command{
 		}
 
 		selectedAnime := searchData[0]
-		selectedAnimeID := *selectedAnime.ID
+		selectedAnimeID, selectedAnimeSlug := getAnimeIDs(selectedAnime)
 
-		episodes, _, _, _, err := getEpisodesAndNames(
+		episodes, _, _, err := getEpisodesAndNames(
+			cmd.Context(),
 			animeSource,
 			false,
-			selectedAnimeID,
-			"",
-			selectedAnime.Title,
+			selectedAnime,
 			logger,
 		)
 		if err != nil {
@@ -1015,7 +1601,7 @@ var downloadCmd = &cobra.Command{
 		var targetEpisode models.Episode
 		foundEpisode := false
 		for _, ep := range episodes {
-			if ep.Number == episodeNumber {
+			if ep.AbsoluteNumber == episodeNumber {
 				targetEpisode = ep
 				foundEpisode = true
 				break
@@ -1040,55 +1626,83 @@ var downloadCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		seasonNum, ok := targetEpisode.Extra["season_num"].(float64)
-		if !ok {
-			fmt.Printf("Error: Could not determine season number for episode %d\n", episodeNumber)
-			os.Exit(1)
-		}
-		seasonIndex := int(seasonNum) - 1
-
-		watchData, _, err := updateWatchAPI(
-			"animecix",
-			episodes,
-			episodeIndex,
-			selectedAnimeID,
-			seasonIndex,
-			0,
-			false,
-			nil,
-		)
+		streams, err := fetchStreams(cmd.Context(), animeSource, episodes, episodeIndex, selectedAnimeID, selectedAnimeSlug, false, "")
 		if err != nil {
 			fmt.Printf("Error getting watch data: %v\n", err)
 			os.Exit(1)
 		}
 
-		urls := watchData["urls"].([]string)
-		if len(urls) == 0 {
-			fmt.Println("No video URLs found for this episode.")
-			os.Exit(1)
-		}
-
-		downloadURL := urls[0]
+		downloadURL := streams[0].URL
 		fmt.Printf("Found download URL: %s\n", downloadURL)
 
-		downloadDir := fmt.Sprintf("indirilenler/%s", selectedAnime.Title)
-		if err := os.MkdirAll(downloadDir, 0755); err != nil {
-			fmt.Printf("Dizin oluşturulurken hata: %v\n", err)
+		downloadPath, err := downloadFilePath(naming.Fields{
+			Anime:    selectedAnime.Title,
+			Title:    targetEpisode.Title,
+			Season:   targetEpisode.Season,
+			Episode:  targetEpisode.Number,
+			Absolute: targetEpisode.AbsoluteNumber,
+			Quality:  streams[0].Quality,
+			Source:   animeSource.Source(),
+		}, false)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
 			os.Exit(1)
 		}
-		downloadPath := fmt.Sprintf("%s/%s.mp4", downloadDir, targetEpisode.Title)
 
-		fmt.Printf("İndiriliyor: %s\n", downloadPath)
-		err = downloader.DownloadFile(downloadURL, downloadPath)
-		if err != nil {
-			fmt.Printf("Error downloading file: %v\n", err)
-			os.Exit(1)
+		if existing := existingDownload(cmd.Context(), downloadPath, downloadURL, streams[0].Headers, logger); existing != "" {
+			if !downloadOverwrite {
+				fmt.Printf("Zaten indirilmiş: %s (yeniden indirmek için --overwrite)\n", existing)
+				return
+			}
+			if err := downloader.Remove(existing); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
 		}
 
-		fmt.Println("Download complete!")
+		// İndirme kalıcı kuyruğa kaydedilir; "downloads list" ile görülür, "downloads resume" ile sürdürülür
+		store, queue := openPersistentQueue(logger)
+		job := downloader.Job{
+			Name:    targetEpisode.Title,
+			URL:     downloadURL,
+			Path:    downloadPath,
+			Quality: streams[0].Quality,
+			Headers: streams[0].Headers,
+		}
+		job.SubtitleURL, job.SubtitleLang = streamSubtitle(animeSource, streams[0])
+		enqueueDownload(store, queue, job, downloader.Origin{
+			Source:         animeSource.Source(),
+			AnimeID:        selectedAnimeID,
+			Slug:           selectedAnimeSlug,
+			Season:         targetEpisode.Season,
+			Episode:        targetEpisode.Number,
+			AbsoluteNumber: targetEpisode.AbsoluteNumber,
+			Quality:        streams[0].Quality,
+			SubtitleURL:    job.SubtitleURL,
+			SubtitleLang:   job.SubtitleLang,
+			Headers:        job.Headers,
+		}, logger)
+
+		summary := queue.Run(cmd.Context())
+		summary.Print(os.Stdout)
+		if summary.Count(downloader.StatusFailed) > 0 || summary.Count(downloader.StatusSkipped) > 0 {
+			os.Exit(1)
+		}
 	},
 }
 
+// appConfig, başlangıçta yapılandırma dosyasından okunan ayarlardır.
+var appConfig = config.Default()
+
+// defaultUIMode, alt komut verilmediğinde kullanılacak arayüz modunu döner.
+// Rofi yalnızca Linux'ta desteklendiğinden diğer sistemlerde her zaman tui kullanılır.
+func defaultUIMode() string {
+	if runtime.GOOS != "linux" {
+		return "tui"
+	}
+	return appConfig.UIMode
+}
+
 func runApp() {
 	logger, err := utils.NewLogger()
 	if err != nil {
@@ -1097,47 +1711,93 @@ func runApp() {
 	defer logger.Close()
 	log.SetFlags(0)
 
-	rootCmd, f := flags.NewFlagsCmd()
+	// Hatalı dosya "config edit" ile düzeltilebilsin diye varsayılan ayarlarla devam edilir
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[!] %s\n[!] Varsayılan ayarlar kullanılıyor.\n", err)
+		cfg = config.Default()
+	}
+	appConfig = cfg
+	if err := httpclient.Configure(httpclient.Settings{
+		Proxy:   cfg.Proxy,
+		Timeout: time.Duration(cfg.RequestTimeout) * time.Second,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "[!] %s\n", err)
+	}
+	tui.SetTheme(tui.Theme{
+		Highlight:   cfg.ThemeHighlight,
+		Normal:      cfg.ThemeNormal,
+		Filter:      cfg.ThemeFilter,
+		InputPrompt: cfg.ThemeInputPrompt,
+		InputText:   cfg.ThemeInputText,
+		Cursor:      cfg.ThemeCursor,
+	})
 
-	rootCmd.AddCommand(downloadCmd)
+	rootCmd, f := flags.NewFlagsCmd(cfg)
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		cache.SetEnabled(!f.NoCache)
+		return f.Validate()
+	}
 
-	commands := rootCmd.Commands()
+	downloadCmd.Flags().StringVarP(&downloadSource, "source", "s", defaultSourceName(),
+		fmt.Sprintf("Kullanılacak kaynak (%s)", strings.Join(sourceNames(), ", ")))
+	downloadCmd.Flags().BoolVar(&downloadOverwrite, "overwrite", false, "Daha önce indirilmiş dosyayı yeniden indirir")
+	rootCmd.AddCommand(downloadCmd)
+	rootCmd.AddCommand(newSearchCmd(logger), newEpisodesCmd(logger), newStreamsCmd(logger))
+	rootCmd.AddCommand(newPlayCmd(f, logger))
+	rootCmd.AddCommand(newConfigCmd())
+	rootCmd.AddCommand(newDownloadsCmd(logger))
+	rootCmd.AddCommand(newCacheCmd())
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "continue",
+		Short: "Son izlenen animelerden birine kaldığı yerden devam eder",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			runMain(cmd.Context(), f, defaultUIMode(), logger, true)
+		},
+	})
 
 	if runtime.GOOS != "linux" {
 		rootCmd.Run = func(cmd *cobra.Command, args []string) {
 			f.RofiMode = false
-			runMain(f, "tui", logger)
+			runMain(cmd.Context(), f, "tui", logger, false)
 		}
 	} else {
+		// Cobra alt komutları ada göre sıraladığı için komutlar sırayla değil adla bulunur
 		var rofiCmd, tuiCmd *cobra.Command
-		if len(commands) > 0 {
-			rofiCmd = commands[0]
-		}
-		if len(commands) > 1 {
-			tuiCmd = commands[1]
+		for _, c := range rootCmd.Commands() {
+			switch c.Name() {
+			case "rofi":
+				rofiCmd = c
+			case "tui":
+				tuiCmd = c
+			}
 		}
 
 		if rofiCmd != nil {
 			rofiCmd.Run = func(cmd *cobra.Command, args []string) {
 				f.RofiMode = true
-				runMain(f, "rofi", logger)
+				runMain(cmd.Context(), f, "rofi", logger, false)
 			}
 		}
 
 		if tuiCmd != nil {
 			tuiCmd.Run = func(cmd *cobra.Command, args []string) {
 				f.RofiMode = false
-				runMain(f, "tui", logger)
+				runMain(cmd.Context(), f, "tui", logger, false)
 			}
 		}
 
 		rootCmd.Run = func(cmd *cobra.Command, args []string) {
-			f.RofiMode = false
-			runMain(f, "tui", logger)
+			uiMode := defaultUIMode()
+			f.RofiMode = uiMode == "rofi"
+			runMain(cmd.Context(), f, uiMode, logger, false)
 		}
 	}
 
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signalContext(context.Background())
+	defer stop()
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
@@ -1146,5 +1806,3 @@ func runApp() {
 func main() {
 	runApp()
 }
-
-